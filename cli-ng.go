@@ -33,10 +33,10 @@ func main() {
 
 	// Global Flags
 	flags := struct {
-		Debug   bool  `short:"d" long:"debug" desc:"Show debugging information"`
-		NoColor bool  `short:"N" long:"no-color" desc:"Disable coloring of output text"`
-		Yes     bool  `short:"y" desc:"Assume yes in all yes/no queries"`
-		Verbose bool  `short:"v" long:"verbose" desc:"Detailed output"`
+		Debug   bool  `short:"d" long:"debug" bind:"debug" desc:"Show debugging information"`
+		NoColor bool  `short:"N" long:"no-color" bind:"no-color" desc:"Disable coloring of output text"`
+		Yes     bool  `short:"y" bind:"yes" desc:"Assume yes in all yes/no queries"`
+		Verbose int   `short:"v" long:"verbose" count:"true" bind:"verbose" desc:"Detailed output"`
 		Level   level `short:"l" arg:"true" long:"level" desc:"Level of something"`
 	}{}
 
@@ -48,6 +48,8 @@ func main() {
 		Version:   "2.0.0",
 		Copyright: "© 2017-2021 Bryan T. Meyers <root@datadrake.com>",
 		License:   license,
+		Pager:     true,
+		Logging:   true,
 	}
 
 	// Setup the Sub-Commands
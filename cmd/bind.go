@@ -0,0 +1,89 @@
+//
+// Copyright 2017-2021 Bryan T. Meyers <root@datadrake.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package cmd
+
+import (
+	"github.com/DataDrake/cli-ng/v2/logging"
+	"github.com/DataDrake/cli-ng/v2/options"
+	"github.com/DataDrake/cli-ng/v2/prompt"
+	"github.com/DataDrake/cli-ng/v2/term"
+	"reflect"
+)
+
+// applyBindings passes the values of global flags with a "bind" tag on to the cli-ng settings they control
+func (r *Root) applyBindings() {
+	var verbosity int64
+	var bound, debug, quiet bool
+	for _, flag := range options.Walk(r.Flags) {
+		field := flag.Value
+		if field.Kind() == reflect.Ptr {
+			if field.IsNil() {
+				continue
+			}
+			field = field.Elem()
+		}
+		switch flag.Field.Tag.Get("bind") {
+		case "no-color":
+			if field.Kind() == reflect.Bool && field.Bool() {
+				term.SetColor(false)
+			}
+		case "yes":
+			if field.Kind() == reflect.Bool && field.Bool() {
+				prompt.AssumeYes = true
+			}
+		case "verbose":
+			bound = true
+			switch field.Kind() {
+			case reflect.Bool:
+				if field.Bool() {
+					verbosity++
+				}
+			case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+				verbosity += field.Int()
+			case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+				verbosity += int64(field.Uint())
+			}
+		case "debug":
+			bound = true
+			debug = debug || (field.Kind() == reflect.Bool && field.Bool())
+		case "quiet":
+			bound = true
+			quiet = quiet || (field.Kind() == reflect.Bool && field.Bool())
+		}
+	}
+	if bound {
+		logging.Default.Level = logLevel(verbosity, debug, quiet)
+	}
+}
+
+// logLevel determines the Level of the Default Logger from the verbosity flags
+//
+// Each level of verbosity shows one more Level than LevelInfo, debug shows at least LevelDebug, and quiet overrides
+// both to only show errors.
+func logLevel(verbosity int64, debug, quiet bool) logging.Level {
+	if quiet {
+		return logging.LevelError
+	}
+	if verbosity > int64(logging.LevelTrace-logging.LevelInfo) {
+		verbosity = int64(logging.LevelTrace - logging.LevelInfo)
+	}
+	level := logging.LevelInfo + logging.Level(verbosity)
+	if debug && level < logging.LevelDebug {
+		level = logging.LevelDebug
+	}
+	return level
+}
@@ -0,0 +1,38 @@
+//
+// Copyright 2017-2021 Bryan T. Meyers <root@datadrake.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package cmd
+
+import (
+	"github.com/DataDrake/cli-ng/v2/logging"
+	"github.com/DataDrake/cli-ng/v2/options"
+)
+
+// warnDeprecated prints a warning if a Sub is deprecated, and for each deprecated flag set on the command line
+func (r *Root) warnDeprecated(c *Sub) {
+	if c.Deprecated != "" {
+		logging.Warnf("command '%s' is deprecated: %s", c.Name, c.Deprecated)
+	}
+	provided := r.ProvidedFlags()
+	for _, flags := range append(r.globalFlags(), c.Flags) {
+		for _, flag := range options.Walk(flags) {
+			msg := flag.Field.Tag.Get("deprecated")
+			if _, ok := provided[flag.Name()]; ok && msg != "" {
+				logging.Warnf("flag '%s' is deprecated: %s", options.FlagDisplay(flag.Name()), msg)
+			}
+		}
+	}
+}
@@ -26,15 +26,29 @@ var Example = Sub{
 	Name:  "example",
 	Alias: "ex",
 	Short: "Example command for testing",
-	Flags: &ExampleFlags{},
-	Args:  &ExampleArgs{},
-	Run:   ExampleRun,
+	Long: `Example prints a message for each of the byte-sized integers it is given.
+
+If no arguments are given, it exits with an error.`,
+	Examples: []Sample{
+		{
+			Command:     "cli-ng example 1 2 3",
+			Explanation: "Get a '1', a '2', and a '3'",
+		},
+		{
+			Command:     "cli-ng example -b",
+			Explanation: "Get booped, but nothing else",
+		},
+	},
+	SeeAlso: []string{"cli-ng-hidden(1)"},
+	Flags:   &ExampleFlags{},
+	Args:    &ExampleArgs{},
+	Run:     ExampleRun,
 }
 
 // ExampleFlags contains the additional flags for the "example" subcommand
 type ExampleFlags struct {
 	Boop bool `short:"b" long:"boop" desc:"You saw nothing"`
-	Bop  bool `long:"bop" desc:"Ouch!"`
+	Bop  bool `long:"bop" negate:"true" desc:"Ouch!"`
 }
 
 // ExampleArgs contains the arguments for the "example" subcommand
@@ -47,6 +61,7 @@ func ExampleRun(r *Root, c *Sub) {
 	// Get the arguments
 	args := c.Args.(*ExampleArgs).Args
 	flags := c.Flags.(*ExampleFlags)
+	r.Log().Debugf("example called with %d argument(s)", len(args))
 	if flags.Boop {
 		fmt.Println("You got booped!!!")
 	}
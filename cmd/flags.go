@@ -18,70 +18,86 @@ package cmd
 
 import (
 	"fmt"
+	"github.com/DataDrake/cli-ng/v2/options"
 	"github.com/DataDrake/cli-ng/v2/term"
-	"io"
-	"os"
 	"reflect"
 	"strings"
-	"text/tabwriter"
 )
 
 // PrintFlags writes out the flags in a struct
 func PrintFlags(flags interface{}) {
-	// Get all the struct elements
-	t := reflect.TypeOf(flags).Elem()
-	args := hasArgs(t)
-	if t.NumField() > 0 {
-		tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
-		if args {
-			fmt.Fprintln(tw, term.Bold("    NAME\tARG\tDESCRIPTION"))
-		} else {
-			fmt.Fprintln(tw, term.Bold("    NAME\tDESCRIPTION"))
-		}
-		// Iterate over arguments
-		for i := 0; i < t.NumField(); i++ {
-			printFlag(tw, t.Field(i), args)
-		}
-		tw.Flush()
-		fmt.Println()
+	set, ok := newFlagSetData("", flags)
+	if !ok {
+		return
+	}
+	if len(set.Flags) > 0 {
+		fmt.Println(flagTable(set))
+	}
+	for _, group := range set.Groups {
+		fmt.Printf("    %s\n\n", term.CurrentTheme().Heading.Sprint(group.Title+":"))
+		fmt.Println(flagTable(group))
 	}
 }
 
-func printFlag(tw io.Writer, f reflect.StructField, args bool) {
-	var name string
-	short := f.Tag.Get("short")
-	if short != "" {
-		name = "-" + short
+// repeatable checks if a flag is counted, so that it may be specified more than once
+func repeatable(f reflect.StructField) bool {
+	return f.Tag.Get("count") != ""
+}
+
+// conflicts gets the names of the other flags in the same "xor" group as a flag
+func conflicts(f options.Flag, all ...interface{}) (names []string) {
+	group := f.Field.Tag.Get("xor")
+	if group == "" {
+		return
 	}
-	if long := f.Tag.Get("long"); long != "" {
-		if short != "" {
-			name += ", "
+	for _, flags := range all {
+		for _, other := range options.Walk(flags) {
+			if shown(other) && other.Field.Tag.Get("xor") == group && other.Name() != f.Name() {
+				names = append(names, options.FlagDisplay(other.Name()))
+			}
 		}
-		name += "--" + long
-	}
-	desc := f.Tag.Get("desc")
-	if args {
-		fmt.Fprintf(tw, term.Resetln("    %s\t%s\t%s"), name, arg(f), desc)
-	} else {
-		fmt.Fprintf(tw, term.Resetln("    %s\t%s"), name, desc)
 	}
+	return
 }
 
-func hasArgs(t reflect.Type) bool {
-	for i := 0; i < t.NumField(); i++ {
-		if t.Field(i).Type.Kind() != reflect.Bool {
-			return true
-		}
+// requires gets the names of the flags which must be provided alongside a flag
+func requires(f reflect.StructField) (names []string) {
+	for _, name := range options.Requires(f.Tag) {
+		names = append(names, options.FlagDisplay(name))
 	}
-	return false
+	return
+}
+
+// shown checks if a flag should appear in help output and man pages, which excludes hidden and deprecated flags
+func shown(f options.Flag) bool {
+	return f.Field.Tag.Get("hidden") == "" && f.Field.Tag.Get("deprecated") == ""
+}
+
+// negatable checks if a bool flag can be turned off with "--no-" before its long name
+func negatable(f reflect.StructField) bool {
+	return f.Tag.Get("negate") != ""
 }
 
 func arg(f reflect.StructField) string {
-	k := f.Type.Kind()
-	switch k {
+	if repeatable(f) {
+		return ""
+	}
+	t := f.Type
+	if t.Kind() == reflect.Ptr && !options.IsFile(t) {
+		t = t.Elem()
+	}
+	switch t.Kind() {
 	case reflect.Bool, reflect.Slice:
 		return ""
 	default:
-		return strings.ToUpper(k.String())
+		return typeName(t)
+	}
+}
+
+// typeName gets the name of the type of a flag or arg for help output, e.g. "INT" or "FILE"
+func typeName(t reflect.Type) string {
+	if options.IsFile(t) {
+		return "FILE"
 	}
+	return strings.ToUpper(t.Kind().String())
 }
@@ -18,6 +18,7 @@ package cmd
 
 import (
 	"fmt"
+	"github.com/DataDrake/cli-ng/v2/options"
 	"io"
 	"os"
 	"reflect"
@@ -54,9 +55,12 @@ func GenerateRootPage(r *Root) error {
 	defer man.Close()
 	genRootHeader(man, r)
 	genRootSynopsis(man, r)
+	genDescription(man, r.Long)
 	genRootSubcommands(man, r)
 	// Global Flags
-	genFlags(man, r.Flags, "GLOBAL FLAGS")
+	genFlags(man, "GLOBAL FLAGS", r.globalFlags()...)
+	genExamples(man, r.Examples)
+	genSeeAlso(man, r.SeeAlso)
 	genFooter(man, r)
 	return nil
 }
@@ -79,15 +83,20 @@ func genRootSynopsis(man io.Writer, r *Root) {
 func genRootSubcommands(man io.Writer, r *Root) {
 	names := getVisibleSubcommands(r)
 	fmt.Fprintln(man, ".SH COMMANDS")
-	for _, name := range names {
-		sub := subcommands[name]
-		genRootSubcommand(man, r, sub, name)
+	for _, group := range r.groupCommands(names) {
+		if len(r.Groups) > 0 {
+			fmt.Fprintf(man, ".SS %s\n", group.Title)
+		}
+		for _, name := range group.Names {
+			sub := subcommands[name]
+			genRootSubcommand(man, r, sub, name)
+		}
 	}
 }
 
 func getVisibleSubcommands(r *Root) (names []string) {
 	for name, cmd := range subcommands {
-		if cmd.Hidden || cmd.SkipMan {
+		if !cmd.listed() || cmd.SkipMan {
 			continue
 		}
 		names = append(names, name)
@@ -103,7 +112,7 @@ func genRootSubcommand(man io.Writer, r *Root, sub *Sub, name string) {
 		fmt.Fprint(man, sub.Short)
 		fmt.Fprintf(man, "\n\nSee \\fI%s(1)\\fR for specific usage\n\n", name)
 	} else {
-		fmt.Fprintf(man, ".B %s (%s) \n", name, sub.Alias)
+		fmt.Fprintf(man, ".B %s (%s) \n", name, strings.Join(sub.allAliases(), ", "))
 		fmt.Fprint(man, sub.Short)
 		fmt.Fprintf(man, "\n\nSee \\fI%s\\-%s(1)\\fR for specific usage\n\n", r.Name, name)
 	}
@@ -112,7 +121,7 @@ func genRootSubcommand(man io.Writer, r *Root, sub *Sub, name string) {
 // GenerateSubPages generates a man-page for every subcommand
 func GenerateSubPages(r *Root) error {
 	for name, cmd := range subcommands {
-		if cmd.Hidden || cmd.SkipMan {
+		if !cmd.listed() || cmd.SkipMan {
 			continue
 		}
 		if err := GenerateSubPage(r, name); err != nil {
@@ -139,11 +148,14 @@ func GenerateSubPage(r *Root, name string) error {
 	sub := subcommands[name]
 	genSubHeader(man, r, sub, name)
 	genSubSynopsis(man, r, sub, name)
+	genDescription(man, sub.Long)
 	genSubArgs(man, sub)
 	// Sub Flags
-	genFlags(man, sub.Flags, strings.ToUpper(name)+" FLAGS")
+	genFlags(man, strings.ToUpper(name)+" FLAGS", sub.Flags)
 	// Global Flags
-	genFlags(man, r.Flags, "GLOBAL FLAGS")
+	genFlags(man, "GLOBAL FLAGS", r.globalFlags()...)
+	genExamples(man, sub.Examples)
+	genSeeAlso(man, sub.SeeAlso)
 	genFooter(man, r)
 	return nil
 }
@@ -176,81 +188,161 @@ func genSubSynopsis(man io.Writer, r *Root, sub *Sub, name string) {
 	if hasFlags {
 		fmt.Fprint(man, "[\\fIOPTIONS...\\fR]")
 	}
+	genSubSynopsisArgs(man, sub)
 }
 
-func genSubArgs(man io.Writer, sub *Sub) {
+func genSubSynopsisArgs(man io.Writer, sub *Sub) {
 	if v := reflect.ValueOf(sub.Args); !v.IsValid() || v.IsZero() {
 		fmt.Fprintf(man, "\n\n")
 		return
 	}
-	args := reflect.TypeOf(sub.Args).Elem()
-	for i := 0; i < args.NumField(); i++ {
-		field := args.Field(i)
-		if k := field.Type.Kind(); k == reflect.Slice {
-			if field.Tag.Get("zero") != "" {
-				fmt.Fprintf(man, " [\\fI%s...\\fR] ", strings.ToUpper(field.Name))
-			} else {
-				fmt.Fprintf(man, " \\fI%s...\\fR ", strings.ToUpper(field.Name))
-			}
+	for _, arg := range newArgData(sub.Args) {
+		name := strings.ToUpper(arg.Name)
+		if arg.Slice {
+			name += "..."
+		}
+		if arg.Optional || (arg.Slice && arg.Min == 0) {
+			fmt.Fprintf(man, " [\\fI%s\\fR]%s ", name, arg.Arity())
 		} else {
-			fmt.Fprintf(man, " \\fI%s\\fR ", strings.ToUpper(field.Name))
+			fmt.Fprintf(man, " \\fI%s\\fR%s ", name, arg.Arity())
 		}
 	}
 	fmt.Fprintln(man)
-	// Arguments
+}
+
+func genSubArgs(man io.Writer, sub *Sub) {
+	if v := reflect.ValueOf(sub.Args); !v.IsValid() || v.IsZero() {
+		return
+	}
+	args := reflect.TypeOf(sub.Args).Elem()
 	if args.NumField() > 0 {
 		fmt.Fprintln(man, ".SH ARGUMENTS")
 		for i := 0; i < args.NumField(); i++ {
 			field := args.Field(i)
 			tags := args.Field(i).Tag
 			fmt.Fprintln(man, ".TP")
-			fmt.Fprintf(man, ".B %s", strings.ToUpper(field.Name))
-			k := field.Type.Kind()
-			switch k {
+			fmt.Fprintf(man, ".B %s", strings.ToUpper(options.ArgName(field)))
+			switch field.Type.Kind() {
 			case reflect.Slice:
-				fmt.Fprintf(man, " \\fI[]%s\\fR", strings.ToUpper(field.Type.Elem().Kind().String()))
+				fmt.Fprintf(man, " \\fI[]%s\\fR", typeName(field.Type.Elem()))
 			default:
-				fmt.Fprintf(man, " \\fI%s\\fR", strings.ToUpper(k.String()))
+				fmt.Fprintf(man, " \\fI%s\\fR", typeName(field.Type))
 			}
-			fmt.Fprintf(man, " %s\n\n", tags.Get("desc"))
+			desc := tags.Get("desc")
+			if def := tags.Get("default"); def != "" {
+				desc += " (default: " + def + ")"
+			}
+			fmt.Fprintf(man, " %s\n\n", desc)
 		}
 	}
 }
 
-// genFlags prints out Flag structs in man-page format
-func genFlags(man io.Writer, flags interface{}, name string) {
-	if v := reflect.ValueOf(flags); !v.IsValid() || v.IsZero() {
+// genFlags prints out Flag structs in man-page format, with ungrouped flags first like in the usage
+func genFlags(man io.Writer, name string, all ...interface{}) {
+	var top []options.Flag
+	var titles []string
+	groups := make(map[string][]options.Flag)
+	for _, flags := range all {
+		for _, flag := range options.Walk(flags) {
+			switch {
+			case !shown(flag):
+			case flag.Group == "":
+				top = append(top, flag)
+			default:
+				if _, ok := groups[flag.Group]; !ok {
+					titles = append(titles, flag.Group)
+				}
+				groups[flag.Group] = append(groups[flag.Group], flag)
+			}
+		}
+	}
+	if len(top) == 0 && len(titles) == 0 {
 		return
 	}
 	fmt.Fprintf(man, ".SH %s\n", name)
-	v := reflect.ValueOf(flags).Elem()
-	flagsType := v.Type()
-	for i := 0; i < flagsType.NumField(); i++ {
-		genFlag(man, flagsType.Field(i))
+	for _, flag := range top {
+		genFlag(man, flag, all...)
+	}
+	for _, title := range titles {
+		fmt.Fprintf(man, ".SS %s\n", title)
+		for _, flag := range groups[title] {
+			genFlag(man, flag, all...)
+		}
 	}
 }
 
-func genFlag(man io.Writer, field reflect.StructField) {
+func genFlag(man io.Writer, flag options.Flag, all ...interface{}) {
 	fmt.Fprintln(man, ".TP")
 	fmt.Fprint(man, ".BR ")
-	tag := field.Tag
-	var short string
-	if short = tag.Get("short"); len(short) > 0 {
+	field := flag.Field
+	short := flag.Short
+	if len(short) > 0 {
 		fmt.Fprintf(man, "\\-%s", short)
 	}
-	if long := tag.Get("long"); len(long) > 0 {
+	if long := flag.Long; len(long) > 0 {
+		prefix := ""
+		if negatable(field) {
+			prefix = "[no\\-]"
+		}
 		if len(short) > 0 {
-			fmt.Fprintf(man, " \", \" \\-\\-%s", long)
+			fmt.Fprintf(man, " \", \" \\-\\-%s%s", prefix, long)
 		} else {
-			fmt.Fprintf(man, "\\-\\-%s", long)
+			fmt.Fprintf(man, "\\-\\-%s%s", prefix, long)
+		}
+		for _, alias := range flag.Aliases {
+			fmt.Fprintf(man, " \", \" \\-\\-%s%s", prefix, alias)
 		}
 	}
-	if k := field.Type.Kind(); k != reflect.Bool {
-		fmt.Fprintf(man, " \" \\fI%s\\fR\n", strings.ToUpper(k.String()))
+	if a := arg(field); a != "" {
+		fmt.Fprintf(man, " \" \\fI%s\\fR\n", a)
 	} else {
 		fmt.Fprintln(man, "\\fR")
 	}
-	fmt.Fprintf(man, "%s\n\n", tag.Get("desc"))
+	desc := field.Tag.Get("desc")
+	if repeatable(field) {
+		desc += " (repeatable)"
+	}
+	if names := conflicts(flag, all...); len(names) > 0 {
+		desc += " (cannot be used with " + strings.Join(names, ", ") + ")"
+	}
+	if names := requires(field); len(names) > 0 {
+		desc += " (requires " + strings.Join(names, ", ") + ")"
+	}
+	fmt.Fprintf(man, "%s\n\n", desc)
+}
+
+// genDescription prints out a multi-paragraph description
+func genDescription(man io.Writer, long string) {
+	if long = strings.TrimSpace(long); len(long) == 0 {
+		return
+	}
+	fmt.Fprintln(man, ".SH DESCRIPTION")
+	for i, paragraph := range strings.Split(long, "\n\n") {
+		if i > 0 {
+			fmt.Fprintln(man, ".PP")
+		}
+		fmt.Fprintln(man, strings.TrimSpace(paragraph))
+	}
+}
+
+func genExamples(man io.Writer, examples []Sample) {
+	if len(examples) == 0 {
+		return
+	}
+	fmt.Fprintln(man, ".SH EXAMPLES")
+	for _, example := range examples {
+		fmt.Fprintln(man, ".TP")
+		fmt.Fprintf(man, ".B %s\n", example.Command)
+		fmt.Fprintf(man, "%s\n\n", example.Explanation)
+	}
+}
+
+func genSeeAlso(man io.Writer, seeAlso []string) {
+	if len(seeAlso) == 0 {
+		return
+	}
+	fmt.Fprintln(man, ".SH SEE ALSO")
+	fmt.Fprintln(man, strings.Join(seeAlso, ", "))
 }
 
 func genFooter(man io.Writer, r *Root) {
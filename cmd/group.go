@@ -0,0 +1,73 @@
+//
+// Copyright 2017-2021 Bryan T. Meyers <root@datadrake.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package cmd
+
+// Group defines a heading for listing related subcommands together
+type Group struct {
+	Name  string
+	Title string
+}
+
+const (
+	// defaultGroupTitle is used for the commands of a Root with no Groups
+	defaultGroupTitle = "Commands"
+	// otherGroupTitle is used for any ungrouped commands of a Root with Groups
+	otherGroupTitle = "Other Commands"
+)
+
+// commandGroup is a titled listing of subcommand names
+type commandGroup struct {
+	Title string
+	Names []string
+}
+
+// groupCommands sorts subcommands into the Groups of this Root, in order, followed by any ungrouped subcommands
+func (r *Root) groupCommands(names []string) (groups []commandGroup) {
+	all := make([]commandGroup, len(r.Groups))
+	index := make(map[string]int)
+	for i, group := range r.Groups {
+		all[i].Title = group.Title
+		if all[i].Title == "" {
+			all[i].Title = group.Name
+		}
+		index[group.Name] = i
+	}
+	var other []string
+	for _, name := range names {
+		if i, ok := index[subcommands[name].Group]; ok {
+			all[i].Names = append(all[i].Names, name)
+		} else {
+			other = append(other, name)
+		}
+	}
+	for _, group := range all {
+		if len(group.Names) > 0 {
+			groups = append(groups, group)
+		}
+	}
+	if len(other) > 0 {
+		title := defaultGroupTitle
+		if len(groups) > 0 {
+			title = otherGroupTitle
+		}
+		groups = append(groups, commandGroup{
+			Title: title,
+			Names: other,
+		})
+	}
+	return
+}
@@ -18,6 +18,7 @@ package cmd
 
 import (
 	"fmt"
+	"github.com/DataDrake/cli-ng/v2/term"
 	"os"
 )
 
@@ -51,7 +52,7 @@ func HelpRun(r *Root, c *Sub) {
 	}
 	// Fail if no matches
 	if sub == nil {
-		fmt.Printf("ERROR: '%s' is not a valid subcommand\n", args.Subcommand)
+		fmt.Printf("%s '%s' is not a valid subcommand\n", term.CurrentTheme().Error.Sprint("ERROR:"), args.Subcommand)
 		r.Usage()
 		os.Exit(1)
 	}
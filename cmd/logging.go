@@ -0,0 +1,41 @@
+//
+// Copyright 2017-2021 Bryan T. Meyers <root@datadrake.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package cmd
+
+import (
+	"github.com/DataDrake/cli-ng/v2/logging"
+)
+
+// logFlags are added to the global flags of a Root with Logging enabled
+type logFlags struct {
+	LogFormat string `long:"log-format" desc:"Log format: text or json"`
+}
+
+// Log returns the Logger for messages from Run functions, as configured by the global flags
+func (r *Root) Log() *logging.Logger {
+	return logging.Default
+}
+
+// applyLogFormat sets the format of the Default Logger from the --log-format flag
+func (r *Root) applyLogFormat() error {
+	format, err := logging.ParseFormat(r.logging.LogFormat)
+	if err != nil {
+		return err
+	}
+	logging.Default.Format = format
+	return nil
+}
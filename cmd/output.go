@@ -0,0 +1,38 @@
+//
+// Copyright 2017-2021 Bryan T. Meyers <root@datadrake.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package cmd
+
+import (
+	"github.com/DataDrake/cli-ng/v2/output"
+	"os"
+)
+
+// outputFlags are added to the global flags of a Root with Output enabled
+type outputFlags struct {
+	Output string `short:"o" long:"output" desc:"Output format: table, json, jsonl, yaml, csv, or template=TEMPLATE"`
+}
+
+// Render writes the results of a command to Stdout, in the format selected with the --output flag
+//
+// Results may be a struct, a pointer to a struct, or a slice of either.
+func (r *Root) Render(results interface{}) error {
+	spec, err := output.ParseSpec(r.output.Output)
+	if err != nil {
+		return err
+	}
+	return spec.Write(os.Stdout, results)
+}
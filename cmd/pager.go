@@ -0,0 +1,78 @@
+//
+// Copyright 2017-2021 Bryan T. Meyers <root@datadrake.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package cmd
+
+import (
+	"bytes"
+	"github.com/DataDrake/cli-ng/v2/term"
+	"os"
+	"os/exec"
+	"strings"
+	"unicode"
+)
+
+// DefaultPager is used to page help output when PAGER is not set
+const DefaultPager = "less -R"
+
+// pagerFlags are added to the global flags of a Root with Pager enabled
+type pagerFlags struct {
+	NoPager bool `long:"no-pager" desc:"Do not pipe help output into a pager"`
+}
+
+// PagerEnv returns the name of the environment variable which overrides PAGER for this program (e.g. CLI_NG_PAGER)
+func (r *Root) PagerEnv() string {
+	return strings.Map(func(c rune) rune {
+		if unicode.IsLetter(c) || unicode.IsDigit(c) {
+			return unicode.ToUpper(c)
+		}
+		return '_'
+	}, r.Name) + "_PAGER"
+}
+
+// pagerCommand gets the command to use for paging, or an empty string if paging is disabled
+func (r *Root) pagerCommand() string {
+	if !r.Pager || r.pager.NoPager {
+		return ""
+	}
+	if pager, ok := os.LookupEnv(r.PagerEnv()); ok {
+		return pager
+	}
+	if pager, ok := os.LookupEnv("PAGER"); ok {
+		return pager
+	}
+	return DefaultPager
+}
+
+// page writes out help output, using a pager if it will not fit in the terminal
+func (r *Root) page(out []byte) {
+	pager := strings.TrimSpace(r.pagerCommand())
+	if pager == "" || pager == "cat" || !term.IsTerminal(os.Stdout) {
+		os.Stdout.Write(out)
+		return
+	}
+	if height := term.Height(); height == 0 || bytes.Count(out, []byte("\n")) < height {
+		os.Stdout.Write(out)
+		return
+	}
+	p := exec.Command("sh", "-c", pager)
+	p.Stdin = bytes.NewReader(out)
+	p.Stdout = os.Stdout
+	p.Stderr = os.Stderr
+	if err := p.Run(); err != nil {
+		os.Stdout.Write(out)
+	}
+}
@@ -19,6 +19,9 @@ package cmd
 var aliases map[string]string
 var subcommands map[string]*Sub
 
+// registrations are every Sub passed to Register, in order, so that duplicates can be reported by Validate
+var registrations []*Sub
+
 func init() {
 	aliases = make(map[string]string)
 	subcommands = make(map[string]*Sub)
@@ -27,5 +30,8 @@ func init() {
 // Register a subcommand for the current root command
 func Register(c *Sub) {
 	subcommands[c.Name] = c
-	aliases[c.Alias] = c.Name
+	for _, alias := range c.allAliases() {
+		aliases[alias] = c.Name
+	}
+	registrations = append(registrations, c)
 }
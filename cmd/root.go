@@ -19,27 +19,46 @@ package cmd
 import (
 	"fmt"
 	"github.com/DataDrake/cli-ng/v2/options"
+	"github.com/DataDrake/cli-ng/v2/output"
 	"github.com/DataDrake/cli-ng/v2/term"
 	"os"
-	"reflect"
 	"sort"
-	"strings"
-	"text/tabwriter"
 )
 
 // Root is the main command that supports multiple Sub commands
 type Root struct {
-	Name      string
-	Short     string
-	Flags     interface{}
-	Single    bool
-	Version   string
-	Copyright string
-	License   string
+	Name             string
+	Short            string
+	Long             string
+	Examples         []Sample
+	SeeAlso          []string
+	Groups           []Group
+	Flags            interface{}
+	Single           bool
+	Version          string
+	Copyright        string
+	License          string
+	UsageTemplate    string
+	SubUsageTemplate string
+	SubTemplates     map[string]string
+	Pager            bool
+	Output           bool
+	Logging          bool
+	ResponseFiles    bool
+
+	pager   pagerFlags
+	output  outputFlags
+	logging logFlags
+	parser  *options.Parser
 }
 
 // Run finds the appropriate CMD and executes it, or prints the global Usage
 func (r *Root) Run() {
+	if autoValidate() {
+		if err := r.Validate(); err != nil {
+			panic(err)
+		}
+	}
 	if !r.Single && len(os.Args) < 2 {
 		r.Usage()
 	}
@@ -47,6 +66,11 @@ func (r *Root) Run() {
 	if !r.Single {
 		args = args[1:]
 	}
+	if r.Pager {
+		for _, arg := range args {
+			r.pager.NoPager = r.pager.NoPager || arg == "--no-pager"
+		}
+	}
 	p, sub := options.NewParser(args, r.Single)
 	if sub == "" {
 		r.Usage()
@@ -62,55 +86,54 @@ func (r *Root) Run() {
 			r.Usage()
 		}
 	}
+	if err := r.checkCollisions(c); err != nil {
+		fmt.Printf("%s %s\n", term.CurrentTheme().Error.Sprint("Error:"), err)
+		os.Exit(1)
+	}
+	if r.ResponseFiles {
+		p.EnableResponseFiles()
+	}
+	if r.Pager {
+		p.AddFlags(&r.pager)
+	}
+	if r.Output {
+		p.AddFlags(&r.output)
+	}
+	if r.Logging {
+		p.AddFlags(&r.logging)
+	}
 	// Parser flags
-	if err := p.Parse(r.Flags, c.Flags, c.Args); err != nil {
-		fmt.Printf("Error: %s\n\n", err)
-		r.SubUsage(c)
+	r.parser = p
+	err := localFlagError(p.Parse(r.Flags, c.Flags, c.Args), c)
+	if err == nil && r.Output {
+		_, err = output.ParseSpec(r.output.Output)
+	}
+	if err == nil && r.Logging {
+		err = r.applyLogFormat()
+	}
+	r.applyBindings()
+	if err != nil {
+		fmt.Printf("%s %s\n\n", term.CurrentTheme().Error.Sprint("Error:"), err)
+		os.Stdout.Write(r.renderUsage(c))
 		os.Exit(1)
 	}
+	r.warnDeprecated(c)
 	c.Run(r, c)
+	if err := p.CloseFiles(); err != nil {
+		fmt.Printf("%s %s\n", term.CurrentTheme().Error.Sprint("Error:"), err)
+		os.Exit(1)
+	}
 }
 
 // Usage prints the usage for this program
 func (r *Root) Usage() {
-	if r.Single {
-		fmt.Printf(term.Bold("NAME:")+" %s\n\n", r.Name)
-	} else {
-		fmt.Printf(term.Bold("USAGE:")+" %s CMD [OPTIONS]\n\n", r.Name)
-	}
-	if len(r.Short) > 0 {
-		fmt.Printf(term.Bold("DESCRIPTION:")+" %s\n\n", r.Short)
-	}
-	r.printSubcommands()
-	if r.Flags != nil {
-		fmt.Printf(term.Bold("GLOBAL FLAGS:\n\n"))
-		PrintFlags(r.Flags)
-	}
+	r.page(r.renderUsage(nil))
 	os.Exit(1)
 }
 
-func (r *Root) printSubcommands() {
-	fmt.Printf(term.Bold("COMMANDS:\n\n"))
-	keys := generateKeys()
-	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
-	if r.Single {
-		fmt.Fprintln(tw, term.Bold("    NAME\tDESCRIPTION"))
-		for _, k := range keys {
-			fmt.Fprintf(tw, term.Resetln("    %s\t%s"), k, subcommands[k].Short)
-		}
-	} else {
-		fmt.Fprintln(tw, term.Bold("    NAME\tALIAS\tDESCRIPTION"))
-		for _, k := range keys {
-			fmt.Fprintf(tw, term.Resetln("    %s\t%s\t%s"), k, subcommands[k].Alias, subcommands[k].Short)
-		}
-	}
-	tw.Flush()
-	fmt.Println()
-}
-
 func generateKeys() (keys []string) {
 	for key, cmd := range subcommands {
-		if cmd.Hidden {
+		if !cmd.listed() {
 			continue
 		}
 		keys = append(keys, key)
@@ -121,59 +144,20 @@ func generateKeys() (keys []string) {
 
 // SubUsage prints a general usage statement for a subcommand
 func (r *Root) SubUsage(c *Sub) {
-	// Print the usage line
-	if r.Single {
-		fmt.Printf(term.Bold("USAGE:")+" %s [OPTIONS]", c.Name)
-	} else {
-		fmt.Printf(term.Bold("USAGE:")+" %s %s [OPTIONS]", r.Name, c.Name)
-	}
-	// Print the argument names
-	if v := reflect.ValueOf(c.Args); v.IsValid() && !v.IsZero() {
-		t := v.Elem().Type()
-		for i := 0; i < t.NumField(); i++ {
-			name := t.Field(i).Name
-			if t.Field(i).Type.Kind() == reflect.Slice {
-				fmt.Printf(" [%s...]", name)
-			} else {
-				fmt.Printf(" <%s>", name)
-			}
-		}
-	}
-	print("\n\n")
-	// Print the description
-	fmt.Printf(term.Bold("DESCRIPTION:")+" %s\n\n", c.Short)
-	// Print the arguments
-	if v := reflect.ValueOf(c.Args); v.IsValid() && !v.IsZero() {
-		t := v.Elem().Type()
-		if t.NumField() > 0 {
-			fmt.Printf(term.Bold("ARGUMENTS:\n\n"))
-			tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
-			fmt.Fprintln(tw, term.Bold("    NAME\tTYPE\tDESCRIPTION"))
-			for i := 0; i < t.NumField(); i++ {
-				field := t.Field(i)
-				k := field.Type.Kind()
-				var kind string
-				switch k {
-				case reflect.Slice:
-					k = field.Type.Elem().Kind()
-					kind = "[]" + strings.ToUpper(k.String())
-				default:
-					kind = strings.ToUpper(k.String())
-				}
-				fmt.Fprintf(tw, term.Resetln("    %s\t%s\t%s"), field.Name, kind, field.Tag.Get("desc"))
-			}
-			tw.Flush()
-			fmt.Println()
-		}
+	r.page(r.renderUsage(c))
+}
+
+// globalFlags gets the flags of this Root, along with any flags which are built-in to cli-ng
+func (r *Root) globalFlags() (flags []interface{}) {
+	flags = append(flags, r.Flags)
+	if r.Pager {
+		flags = append(flags, &r.pager)
 	}
-	// Print global flags
-	if c.Flags != nil {
-		fmt.Printf(term.Bold("%s FLAGS:\n\n"), strings.ToUpper(c.Name))
-		PrintFlags(c.Flags)
+	if r.Output {
+		flags = append(flags, &r.output)
 	}
-	// Print global flags
-	if r.Flags != nil {
-		fmt.Printf(term.Bold("GLOBAL FLAGS:\n\n"))
-		PrintFlags(r.Flags)
+	if r.Logging {
+		flags = append(flags, &r.logging)
 	}
+	return
 }
@@ -0,0 +1,126 @@
+//
+// Copyright 2017-2021 Bryan T. Meyers <root@datadrake.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"github.com/DataDrake/cli-ng/v2/options"
+	"sort"
+	"strings"
+)
+
+// flagNames lists every name that the flags in a set can be typed as (e.g. "-v", "--verbose", or "--no-color")
+func flagNames(all ...interface{}) (names []string) {
+	for _, flags := range all {
+		for _, flag := range options.Walk(flags) {
+			if flag.Short != "" {
+				names = append(names, "-"+flag.Short)
+			}
+			for _, long := range append([]string{flag.Long}, flag.Aliases...) {
+				if long == "" {
+					continue
+				}
+				names = append(names, "--"+long)
+				if negatable(flag.Field) {
+					names = append(names, "--no-"+long)
+				}
+			}
+		}
+	}
+	return
+}
+
+// sortedSubcommands lists the names of every registered subcommand, including hidden ones, in order
+func sortedSubcommands() (names []string) {
+	for name := range subcommands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return
+}
+
+// collisions describes every flag of a subcommand with the same name as a global flag
+//
+// Global flags are persistent, applying to every subcommand, so a local flag of the same name could never be set.
+func (r *Root) collisions() (problems []string) {
+	for _, c := range registrations {
+		problems = append(problems, r.subCollisions(c)...)
+	}
+	return
+}
+
+// subCollisions describes every flag of a single subcommand with the same name as a global flag
+func (r *Root) subCollisions(c *Sub) (problems []string) {
+	global := make(map[string]bool)
+	for _, name := range flagNames(r.globalFlags()...) {
+		global[name] = true
+	}
+	for _, name := range flagNames(c.Flags) {
+		if global[name] {
+			problems = append(problems, fmt.Sprintf("subcommand '%s' %s: flag '%s' collides with a global flag",
+				c.Name, describe("flags", c.Flags), name))
+		}
+	}
+	return
+}
+
+// checkCollisions reports any flags of a subcommand which collide with global flags, as a ValidationError
+//
+// Unlike Validate, this is checked on every run, so that a colliding flag is never silently shadowed.
+func (r *Root) checkCollisions(c *Sub) error {
+	if problems := r.subCollisions(c); len(problems) > 0 {
+		return &ValidationError{Problems: problems}
+	}
+	return nil
+}
+
+// duplicates lists every name which appears more than once
+func duplicates(names []string) (dups []string) {
+	seen := make(map[string]int)
+	for _, name := range names {
+		if seen[name]++; seen[name] == 2 {
+			dups = append(dups, name)
+		}
+	}
+	return
+}
+
+// localFlagError explains that an unknown flag is local to other subcommands, if it is, without naming hidden ones
+func localFlagError(err error, c *Sub) error {
+	var unknown *options.UnknownFlagError
+	if !errors.As(err, &unknown) {
+		return err
+	}
+	var owners []string
+	for _, sub := range sortedSubcommands() {
+		if other := subcommands[sub]; other == c || !other.listed() {
+			continue
+		}
+		for _, name := range flagNames(subcommands[sub].Flags) {
+			if name == unknown.Display() {
+				owners = append(owners, sub)
+				break
+			}
+		}
+	}
+	if len(owners) == 0 {
+		return err
+	}
+	return fmt.Errorf("flag '%s' is not valid for '%s', it only applies to: %s", unknown.Display(), c.Name,
+		strings.Join(owners, ", "))
+}
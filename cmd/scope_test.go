@@ -0,0 +1,56 @@
+//
+// Copyright 2017-2021 Bryan T. Meyers <root@datadrake.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package cmd
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+type scopeGlobalFlags struct {
+	Verbose bool `short:"v" long:"verbose" desc:"Verbose"`
+}
+
+type scopeSubFlags struct {
+	Version bool `short:"v" desc:"Version"`
+}
+
+type scopeOtherFlags struct {
+	Force bool `short:"f" long:"force" desc:"Force"`
+}
+
+func TestCheckCollisions(t *testing.T) {
+	r := &Root{Name: "test", Flags: &scopeGlobalFlags{}, Pager: true}
+	c := &Sub{Name: "get", Flags: &scopeSubFlags{}}
+	err := r.checkCollisions(c)
+	var verr *ValidationError
+	if !errors.As(err, &verr) {
+		t.Fatalf("expected a ValidationError, found: %v", err)
+	}
+	if len(verr.Problems) != 1 || !strings.Contains(verr.Problems[0], "flag '-v' collides") {
+		t.Errorf("unexpected problems: %q", verr.Problems)
+	}
+	if err := r.checkCollisions(&Sub{Name: "put", Flags: &scopeOtherFlags{}}); err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+	if err := r.checkCollisions(&Sub{Name: "page", Flags: &struct {
+		NoPager bool `long:"no-pager"`
+	}{}}); err == nil {
+		t.Error("expected a collision with the built-in --no-pager flag")
+	}
+}
@@ -0,0 +1,51 @@
+//
+// Copyright 2017-2021 Bryan T. Meyers <root@datadrake.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package cmd
+
+import (
+	"fmt"
+	"github.com/DataDrake/cli-ng/v2/options"
+)
+
+// ProvidedFlags returns the name of every flag that has been set, along with where its value came from
+func (r *Root) ProvidedFlags() map[string]options.Source {
+	if r.parser == nil {
+		return make(map[string]options.Source)
+	}
+	return r.parser.Provided()
+}
+
+// FlagSource gets where the value of a flag came from, by its long or short name
+//
+// If the flag has not been set, ok is false.
+func (r *Root) FlagSource(name string) (src options.Source, ok bool) {
+	if r.parser == nil {
+		return
+	}
+	return r.parser.Source(name)
+}
+
+// SetFlag sets a global or subcommand flag from a raw value, unless it was already set from a higher precedence Source
+//
+// This allows Run functions to layer values from environment variables or configuration files under the values
+// provided on the command line.
+func (r *Root) SetFlag(name, value string, src options.Source) error {
+	if r.parser == nil {
+		return fmt.Errorf("invalid flag '%s'", name)
+	}
+	return r.parser.Set(name, value, src)
+}
@@ -18,12 +18,42 @@ package cmd
 
 // Sub is a type for all commands
 type Sub struct {
-	Name    string
-	Alias   string
-	Short   string
-	Hidden  bool
-	SkipMan bool
-	Args    interface{}
-	Flags   interface{}
-	Run     func(r *Root, c *Sub)
+	Name       string
+	Alias      string
+	Aliases    []string
+	Short      string
+	Group      string
+	Long       string
+	Examples   []Sample
+	SeeAlso    []string
+	Hidden     bool
+	Deprecated string
+	SkipMan    bool
+	Args       interface{}
+	Flags      interface{}
+	Run        func(r *Root, c *Sub)
+}
+
+// Sample is an example invocation of a command, along with an explanation of what it does
+type Sample struct {
+	Command     string
+	Explanation string
+}
+
+// listed checks if this Sub should appear in usage and man pages, which excludes hidden and deprecated commands
+func (c *Sub) listed() bool {
+	return !c.Hidden && c.Deprecated == ""
+}
+
+// allAliases lists the primary Alias of this Sub, if any, followed by its other non-empty Aliases
+func (c *Sub) allAliases() (all []string) {
+	if c.Alias != "" {
+		all = append(all, c.Alias)
+	}
+	for _, alias := range c.Aliases {
+		if alias != "" {
+			all = append(all, alias)
+		}
+	}
+	return
 }
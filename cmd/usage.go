@@ -0,0 +1,416 @@
+//
+// Copyright 2017-2021 Bryan T. Meyers <root@datadrake.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/DataDrake/cli-ng/v2/options"
+	"github.com/DataDrake/cli-ng/v2/term"
+	"reflect"
+	"strings"
+	"text/template"
+)
+
+// DefaultUsageTemplate is the template used by Root.Usage unless overridden
+const DefaultUsageTemplate = `{{if .Root.Single}}{{heading "NAME:"}} {{.Root.Name}}{{else}}{{heading "USAGE:"}} {{.Root.Name}} CMD [OPTIONS]{{end}}
+
+{{with .Root.Short}}{{section "DESCRIPTION:" .}}
+
+{{end}}{{with .Root.Long}}{{indent 4 .}}
+
+{{end}}{{range .Groups}}{{heading (printf "%s:" (upper .Title))}}
+
+{{commandTable $.Root.Single .Commands}}
+{{end}}{{range .Flags}}{{template "flags" .}}{{end}}{{template "extras" .Root}}`
+
+// DefaultSubUsageTemplate is the template used by Root.SubUsage unless overridden
+const DefaultSubUsageTemplate = `{{heading "USAGE:"}} {{if not .Root.Single}}{{.Root.Name}} {{end}}{{.Sub.Name}} [OPTIONS]{{range .Args}} {{.Usage}}{{end}}
+
+{{section "DESCRIPTION:" .Sub.Short}}
+
+{{with .Sub.Long}}{{indent 4 .}}
+
+{{end}}{{with .Args}}{{heading "ARGUMENTS:"}}
+
+{{argTable .}}
+{{end}}{{range .Flags}}{{template "flags" .}}{{end}}{{template "extras" .Sub}}`
+
+// sharedTemplates are available to all usage templates
+const sharedTemplates = `{{define "flags"}}{{heading (printf "%s:" .Title)}}
+
+{{if .Flags}}{{flagTable .}}
+{{end}}{{range .Groups}}    {{heading (printf "%s:" .Title)}}
+
+{{flagTable .}}
+{{end}}{{end}}{{define "extras"}}{{with .Examples}}{{heading "EXAMPLES:"}}
+
+{{range .}}    $ {{.Command}}
+{{with .Explanation}}{{indent 8 .}}
+{{end}}
+{{end}}{{end}}{{with .SeeAlso}}{{heading "SEE ALSO:"}} {{join . ", "}}
+
+{{end}}{{end}}`
+
+// UsageData is the model passed to the usage templates
+type UsageData struct {
+	Root     *Root
+	Sub      *Sub
+	Commands []CommandData
+	Groups   []CommandGroupData
+	Args     []ArgData
+	Flags    []FlagSetData
+}
+
+// CommandData describes a single visible subcommand
+type CommandData struct {
+	Name  string
+	Alias string
+	Short string
+}
+
+// CommandGroupData describes a titled listing of subcommands
+type CommandGroupData struct {
+	Title    string
+	Commands []CommandData
+}
+
+// ArgData describes a single argument of a subcommand
+type ArgData struct {
+	Name     string
+	Type     string
+	Desc     string
+	Slice    bool
+	Optional bool
+	Default  string
+	Min      int
+	Max      int
+}
+
+// Usage returns the argument as shown in a usage line (e.g. "<SRC>", "[DST]", "[FILES...]", or "<FILES>...{1,3}")
+func (a ArgData) Usage() string {
+	if !a.Slice {
+		if a.Optional {
+			return "[" + a.Name + "]"
+		}
+		return "<" + a.Name + ">"
+	}
+	usage := "<" + a.Name + ">..."
+	if a.Min == 0 {
+		usage = "[" + a.Name + "...]"
+	}
+	return usage + a.Arity()
+}
+
+// Arity returns the bounds of a slice argument (e.g. "{1,3}" or "{2,}"), or nothing if they are the defaults
+func (a ArgData) Arity() string {
+	switch {
+	case !a.Slice, a.Max == 0 && a.Min <= 1:
+		return ""
+	case a.Max == 0:
+		return fmt.Sprintf("{%d,}", a.Min)
+	default:
+		return fmt.Sprintf("{%d,%d}", a.Min, a.Max)
+	}
+}
+
+// FlagSetData describes a titled set of flags, along with any groups of flags from nested structs
+type FlagSetData struct {
+	Title   string
+	HasArgs bool
+	Flags   []FlagData
+	Groups  []FlagSetData
+
+	nested bool
+}
+
+// FlagData describes a single flag
+type FlagData struct {
+	Short      string
+	Long       string
+	Arg        string
+	Desc       string
+	Repeatable bool
+	Negatable  bool
+	Conflicts  []string
+	Requires   []string
+}
+
+// Name returns the combined short and long names of a flag (e.g. "-v, --verbose" or "--[no-]color")
+func (f FlagData) Name() (name string) {
+	if f.Short != "" {
+		name = "-" + f.Short
+	}
+	if f.Long != "" {
+		if f.Short != "" {
+			name += ", "
+		}
+		name += "--"
+		if f.Negatable {
+			name += "[no-]"
+		}
+		name += f.Long
+	}
+	return
+}
+
+// usageFuncs are the functions available to usage templates
+var usageFuncs = template.FuncMap{
+	"bold":         term.Bold,
+	"heading":      func(s string) string { return term.CurrentTheme().Heading.Sprint(s) },
+	"upper":        strings.ToUpper,
+	"join":         strings.Join,
+	"indent":       indent,
+	"section":      section,
+	"commandTable": commandTable,
+	"argTable":     argTable,
+	"flagTable":    flagTable,
+}
+
+// newUsageData builds the template model for a Root and an optional Sub
+func newUsageData(r *Root, c *Sub) *UsageData {
+	data := &UsageData{
+		Root: r,
+		Sub:  c,
+	}
+	if c == nil {
+		for _, group := range r.groupCommands(generateKeys()) {
+			groupData := CommandGroupData{
+				Title: group.Title,
+			}
+			for _, name := range group.Names {
+				groupData.Commands = append(groupData.Commands, newCommandData(name))
+			}
+			data.Commands = append(data.Commands, groupData.Commands...)
+			data.Groups = append(data.Groups, groupData)
+		}
+	} else {
+		data.Args = newArgData(c.Args)
+		if set, ok := newFlagSetData(strings.ToUpper(c.Name)+" FLAGS", c.Flags); ok {
+			data.Flags = append(data.Flags, set)
+		}
+	}
+	if set, ok := newFlagSetData("GLOBAL FLAGS", r.globalFlags()...); ok {
+		data.Flags = append(data.Flags, set)
+	}
+	return data
+}
+
+func newCommandData(name string) CommandData {
+	sub := subcommands[name]
+	data := CommandData{
+		Name:  name,
+		Short: sub.Short,
+	}
+	if all := sub.allAliases(); len(all) > 0 {
+		data.Alias = all[0]
+	}
+	return data
+}
+
+func newArgData(args interface{}) (data []ArgData) {
+	v := reflect.ValueOf(args)
+	if !v.IsValid() || v.IsZero() {
+		return
+	}
+	t := v.Elem().Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		arg := ArgData{
+			Name:     options.ArgName(field),
+			Desc:     field.Tag.Get("desc"),
+			Optional: options.ArgOptional(field),
+			Default:  field.Tag.Get("default"),
+		}
+		switch field.Type.Kind() {
+		case reflect.Slice:
+			arg.Slice = true
+			arg.Min, arg.Max, _ = options.ArgBounds(field)
+			arg.Type = "[]" + typeName(field.Type.Elem())
+		default:
+			arg.Type = typeName(field.Type)
+		}
+		data = append(data, arg)
+	}
+	return
+}
+
+func newFlagSetData(title string, all ...interface{}) (set FlagSetData, ok bool) {
+	set.Title = title
+	groups := make(map[string]int)
+	for _, flags := range all {
+		for _, flag := range options.Walk(flags) {
+			if !shown(flag) {
+				continue
+			}
+			data := FlagData{
+				Short:      flag.Short,
+				Long:       flag.Long,
+				Arg:        arg(flag.Field),
+				Desc:       flag.Field.Tag.Get("desc"),
+				Repeatable: repeatable(flag.Field),
+				Negatable:  negatable(flag.Field),
+				Conflicts:  conflicts(flag, all...),
+				Requires:   requires(flag.Field),
+			}
+			if flag.Group == "" {
+				set.HasArgs = set.HasArgs || data.Arg != ""
+				set.Flags = append(set.Flags, data)
+				continue
+			}
+			i, ok := groups[flag.Group]
+			if !ok {
+				i = len(set.Groups)
+				groups[flag.Group] = i
+				set.Groups = append(set.Groups, FlagSetData{
+					Title:  flag.Group,
+					nested: true,
+				})
+			}
+			group := &set.Groups[i]
+			group.HasArgs = group.HasArgs || data.Arg != ""
+			group.Flags = append(group.Flags, data)
+		}
+	}
+	ok = len(set.Flags) > 0 || len(set.Groups) > 0
+	return
+}
+
+// renderUsage executes the usage template for the root or a subcommand
+func (r *Root) renderUsage(c *Sub) []byte {
+	t, err := template.New("usage").Funcs(usageFuncs).Parse(sharedTemplates)
+	if err != nil {
+		panic(err)
+	}
+	if _, err = t.Parse(r.usageTemplate(c)); err != nil {
+		panic(err)
+	}
+	var out bytes.Buffer
+	if err = t.Execute(&out, newUsageData(r, c)); err != nil {
+		panic(err)
+	}
+	return out.Bytes()
+}
+
+// usageTemplate selects the template to use for the root or a subcommand
+func (r *Root) usageTemplate(c *Sub) string {
+	if c == nil {
+		if r.UsageTemplate != "" {
+			return r.UsageTemplate
+		}
+		return DefaultUsageTemplate
+	}
+	if tmpl := r.SubTemplates[c.Name]; tmpl != "" {
+		return tmpl
+	}
+	if r.SubUsageTemplate != "" {
+		return r.SubUsageTemplate
+	}
+	return DefaultSubUsageTemplate
+}
+
+// newTable creates a Table for usage output, which fits in the width of the terminal
+func newTable(headers ...string) *term.Table {
+	t := term.NewTable(headers...)
+	t.Indent = 4
+	t.Width = term.Width()
+	t.HeaderStyle = term.CurrentTheme().TableHeader
+	t.Columns[len(t.Columns)-1].Wrap = true
+	return t
+}
+
+func commandTable(single bool, cmds []CommandData) string {
+	theme := term.CurrentTheme()
+	t := newTable("NAME", "ALIAS", "DESCRIPTION")
+	if single {
+		t = newTable("NAME", "DESCRIPTION")
+	}
+	for _, c := range cmds {
+		if single {
+			t.AddRow(theme.Command.Sprint(c.Name), c.Short)
+		} else {
+			t.AddRow(theme.Command.Sprint(c.Name), c.Alias, c.Short)
+		}
+	}
+	return t.String()
+}
+
+func argTable(args []ArgData) string {
+	t := newTable("NAME", "TYPE", "DESCRIPTION")
+	for _, arg := range args {
+		desc := arg.Desc
+		if arg.Default != "" {
+			desc += " (default: " + arg.Default + ")"
+		}
+		t.AddRow(arg.Name, arg.Type, desc)
+	}
+	return t.String()
+}
+
+func flagTable(set FlagSetData) string {
+	theme := term.CurrentTheme()
+	t := newTable("NAME", "DESCRIPTION")
+	if set.HasArgs {
+		t = newTable("NAME", "ARG", "DESCRIPTION")
+	}
+	if set.nested {
+		t.Indent += 4
+	}
+	for _, f := range set.Flags {
+		desc := f.Desc
+		if f.Repeatable {
+			desc += " (repeatable)"
+		}
+		if len(f.Conflicts) > 0 {
+			desc += " (conflicts with " + strings.Join(f.Conflicts, ", ") + ")"
+		}
+		if len(f.Requires) > 0 {
+			desc += " (requires " + strings.Join(f.Requires, ", ") + ")"
+		}
+		if set.HasArgs {
+			t.AddRow(theme.Flag.Sprint(f.Name()), f.Arg, desc)
+		} else {
+			t.AddRow(theme.Flag.Sprint(f.Name()), desc)
+		}
+	}
+	return t.String()
+}
+
+// section prints a heading followed by text on the same line, wrapped to fit the terminal with a hanging indent
+func section(title, text string) string {
+	n := term.StringWidth(title) + 1
+	wrapped := term.Indent(text, term.Width(), n, n)
+	return term.CurrentTheme().Heading.Sprint(title) + " " + strings.TrimLeft(wrapped, " ")
+}
+
+// indent adds n spaces to the start of every non-empty line, wrapping them to fit the terminal
+//
+// Lines which already start with whitespace are treated as preformatted and are not wrapped.
+func indent(n int, text string) string {
+	width := term.Width()
+	lines := strings.Split(strings.TrimSpace(text), "\n")
+	for i, line := range lines {
+		switch {
+		case strings.TrimSpace(line) == "":
+		case strings.TrimLeft(line, " \t") != line:
+			lines[i] = strings.Repeat(" ", n) + line
+		default:
+			lines[i] = term.Indent(line, width, n, n)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
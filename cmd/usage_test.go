@@ -0,0 +1,42 @@
+//
+// Copyright 2017-2021 Bryan T. Meyers <root@datadrake.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package cmd
+
+import (
+	"github.com/DataDrake/cli-ng/v2/term"
+	"os"
+	"testing"
+)
+
+const narrowSubUsage = `USAGE: test get [OPTIONS]
+
+DESCRIPTION: Fetch every remote object
+             which matches the filters
+             and save it locally
+
+`
+
+func TestSubUsageNarrow(t *testing.T) {
+	term.SetColor(false)
+	os.Setenv("COLUMNS", "40")
+	defer os.Unsetenv("COLUMNS")
+	r := &Root{Name: "test"}
+	c := &Sub{Name: "get", Short: "Fetch every remote object which matches the filters and save it locally"}
+	if out := string(r.renderUsage(c)); out != narrowSubUsage {
+		t.Errorf("unexpected usage, expected:\n%s\nfound:\n%s", narrowSubUsage, out)
+	}
+}
@@ -0,0 +1,126 @@
+//
+// Copyright 2017-2021 Bryan T. Meyers <root@datadrake.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package cmd
+
+import (
+	"fmt"
+	"github.com/DataDrake/cli-ng/v2/options"
+	"os"
+	"reflect"
+	"strings"
+)
+
+// ValidationError lists every problem found with the definitions of a Root and its subcommands
+type ValidationError struct {
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return "invalid command definitions:\n    " + strings.Join(e.Problems, "\n    ")
+}
+
+// describe names a struct of flags or args, including its type if it is not anonymous (e.g. "flags *cmd.ExampleFlags")
+func describe(kind string, v interface{}) string {
+	t := reflect.TypeOf(v)
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Name() == "" {
+		return kind
+	}
+	return fmt.Sprintf("%s %T", kind, v)
+}
+
+// autoValidate checks if Run should panic on invalid command definitions, which happens in debug builds and tests
+func autoValidate() bool {
+	return debugBuild || strings.HasSuffix(os.Args[0], ".test")
+}
+
+// Validate checks the Root and every registered Sub for mistakes in their definitions, returning a ValidationError
+// which describes all of them
+//
+// This includes duplicate names and aliases, flags which collide, unsupported flag and arg types, and groups which are
+// not defined by the Root.
+func (r *Root) Validate() error {
+	var problems []string
+	add := func(format string, a ...interface{}) {
+		problems = append(problems, fmt.Sprintf(format, a...))
+	}
+	for _, problem := range options.FlagProblems(r.Flags, r.globalFlags()...) {
+		add("global %s: %s", describe("flags", r.Flags), problem)
+	}
+	for _, name := range duplicates(flagNames(r.globalFlags()...)) {
+		add("global flag '%s' is declared more than once", name)
+	}
+	groups := make(map[string]bool)
+	for _, group := range r.Groups {
+		if groups[group.Name] {
+			add("group '%s' is declared more than once", group.Name)
+		}
+		groups[group.Name] = true
+	}
+	names := make(map[string]*Sub)
+	owners := make(map[string]*Sub)
+	for _, c := range registrations {
+		if c.Name == "" {
+			add("subcommand with aliases '%s' has no name", strings.Join(c.allAliases(), ", "))
+		}
+		if other := names[c.Name]; other != nil && other != c {
+			add("subcommand '%s' is registered more than once", c.Name)
+		}
+		names[c.Name] = c
+	}
+	for _, c := range registrations {
+		for _, alias := range c.Aliases {
+			if alias == "" {
+				add("subcommand '%s' has an empty alias", c.Name)
+			}
+		}
+		for _, alias := range c.allAliases() {
+			if other := names[alias]; other != nil && other != c {
+				add("alias '%s' of subcommand '%s' is the name of subcommand '%s'", alias, c.Name, other.Name)
+			}
+			if other := owners[alias]; other != nil {
+				add("alias '%s' of subcommand '%s' is also an alias of subcommand '%s'", alias, c.Name, other.Name)
+			}
+			owners[alias] = c
+		}
+	}
+	for _, c := range registrations {
+		name := c.Name
+		if c.Run == nil {
+			add("subcommand '%s' has no Run function", name)
+		}
+		if c.Group != "" && !groups[c.Group] {
+			add("subcommand '%s' is in undefined group '%s'", name, c.Group)
+		}
+		for _, problem := range options.FlagProblems(c.Flags, append(r.globalFlags(), c.Flags)...) {
+			add("subcommand '%s' %s: %s", name, describe("flags", c.Flags), problem)
+		}
+		for _, flag := range duplicates(flagNames(c.Flags)) {
+			add("subcommand '%s' declares flag '%s' more than once", name, flag)
+		}
+		for _, problem := range options.ArgProblems(c.Args) {
+			add("subcommand '%s' %s: %s", name, describe("args", c.Args), problem)
+		}
+	}
+	problems = append(problems, r.collisions()...)
+	if len(problems) > 0 {
+		return &ValidationError{Problems: problems}
+	}
+	return nil
+}
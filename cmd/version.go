@@ -34,17 +34,23 @@ var Version = Sub{
 func VersionRun(r *Root, c *Sub) {
 	fmt.Printf("%s %s\n", r.Name, r.Version)
 	if len(r.Copyright) > 0 {
-		if strings.Contains(r.Copyright, "\n") {
-			fmt.Printf(term.Bold("\nCOPYRIGHT:")+"\n\n%s\n", r.Copyright)
-		} else {
-			fmt.Printf(term.Bold("\nCOPYRIGHT:")+"\n    %s\n", r.Copyright)
-		}
+		printNotice("COPYRIGHT:", r.Copyright)
 	}
 	if len(r.License) > 0 {
-		if strings.Contains(r.License, "\n") {
-			fmt.Printf(term.Bold("\nLICENSE:")+"\n\n%s\n", r.License)
-		} else {
-			fmt.Printf(term.Bold("\nLICENSE:")+"\n    %s\n", r.License)
-		}
+		printNotice("LICENSE:", r.License)
 	}
 }
+
+// printNotice prints a titled block of text, wrapped to fit the terminal
+func printNotice(title, text string) {
+	width := term.Width()
+	if !strings.Contains(text, "\n") {
+		fmt.Printf(term.CurrentTheme().Heading.Sprint("\n"+title)+"\n%s\n", term.Indent(text, width, 4, 4))
+		return
+	}
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		lines[i] = strings.Join(term.Wrap(line, width), "\n")
+	}
+	fmt.Printf(term.CurrentTheme().Heading.Sprint("\n"+title)+"\n\n%s\n", strings.Join(lines, "\n"))
+}
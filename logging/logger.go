@@ -0,0 +1,206 @@
+//
+// Copyright 2017-2021 Bryan T. Meyers <root@datadrake.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package logging
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/DataDrake/cli-ng/v2/term"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Level is the severity of a log message
+type Level int
+
+const (
+	// LevelError is for failures which stop the program from doing what was asked
+	LevelError Level = iota
+	// LevelWarn is for problems which the program can recover from
+	LevelWarn
+	// LevelInfo is for progress which the user would normally want to see
+	LevelInfo
+	// LevelDebug is for details which help with diagnosing problems
+	LevelDebug
+	// LevelTrace is for very detailed diagnostics
+	LevelTrace
+)
+
+// levelNames are the names of each Level, as used in JSON output
+var levelNames = []string{"error", "warn", "info", "debug", "trace"}
+
+// String returns the name of this Level
+func (l Level) String() string {
+	if l < LevelError || int(l) >= len(levelNames) {
+		return fmt.Sprintf("level(%d)", int(l))
+	}
+	return levelNames[l]
+}
+
+// label returns the prefix of a text message at this Level, styled with the current term Theme if supported by out
+func (l Level) label(out io.Writer) string {
+	theme := term.CurrentTheme()
+	switch l {
+	case LevelError:
+		return theme.Error.SprintFor(out, "ERROR:")
+	case LevelWarn:
+		return theme.Warning.SprintFor(out, "WARNING:")
+	case LevelInfo:
+		return theme.Info.SprintFor(out, "INFO:")
+	case LevelDebug:
+		return theme.Debug.SprintFor(out, "DEBUG:")
+	default:
+		return theme.Debug.SprintFor(out, "TRACE:")
+	}
+}
+
+// Format is a way of rendering log messages
+type Format string
+
+const (
+	// Text renders each message as a colored line, prefixed by its Level
+	Text Format = "text"
+	// JSON renders each message as a JSON object on its own line
+	JSON Format = "json"
+)
+
+// ParseFormat parses the name of a Format, where an empty name is Text
+func ParseFormat(raw string) (Format, error) {
+	switch f := Format(raw); f {
+	case "":
+		return Text, nil
+	case Text, JSON:
+		return f, nil
+	}
+	return Text, fmt.Errorf("unknown log format '%s'", raw)
+}
+
+// Logger writes leveled messages, discarding any which are more detailed than its Level
+type Logger struct {
+	Level  Level
+	Format Format
+
+	lock sync.Mutex
+	out  io.Writer
+}
+
+// New creates a Logger which writes Text messages at LevelInfo or above to out
+func New(out io.Writer) *Logger {
+	return &Logger{
+		Level:  LevelInfo,
+		Format: Text,
+		out:    out,
+	}
+}
+
+// SetOutput changes where messages are written, e.g. to a term.Progress so that they do not clobber its indicators
+func (l *Logger) SetOutput(out io.Writer) {
+	l.lock.Lock()
+	l.out = out
+	l.lock.Unlock()
+}
+
+// Default is the Logger used by the package-level functions, which writes to Stderr
+var Default = New(os.Stderr)
+
+// Enabled checks if messages at the specified Level will be written
+func (l *Logger) Enabled(level Level) bool {
+	return level <= l.Level
+}
+
+// entry is a single message in the JSON Format
+type entry struct {
+	Time    string `json:"time"`
+	Level   string `json:"level"`
+	Message string `json:"msg"`
+}
+
+// Log writes a message at the specified Level
+func (l *Logger) Log(level Level, msg string) {
+	if !l.Enabled(level) {
+		return
+	}
+	msg = strings.TrimRight(msg, "\n")
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	var line string
+	switch l.Format {
+	case JSON:
+		raw, _ := json.Marshal(entry{
+			Time:    time.Now().Format(time.RFC3339),
+			Level:   level.String(),
+			Message: msg,
+		})
+		line = string(raw) + "\n"
+	default:
+		line = level.label(l.out) + " " + msg + "\n"
+	}
+	io.WriteString(l.out, line)
+}
+
+// Errorf writes a formatted message at LevelError
+func (l *Logger) Errorf(format string, a ...interface{}) {
+	l.Log(LevelError, fmt.Sprintf(format, a...))
+}
+
+// Warnf writes a formatted message at LevelWarn
+func (l *Logger) Warnf(format string, a ...interface{}) {
+	l.Log(LevelWarn, fmt.Sprintf(format, a...))
+}
+
+// Infof writes a formatted message at LevelInfo
+func (l *Logger) Infof(format string, a ...interface{}) {
+	l.Log(LevelInfo, fmt.Sprintf(format, a...))
+}
+
+// Debugf writes a formatted message at LevelDebug
+func (l *Logger) Debugf(format string, a ...interface{}) {
+	l.Log(LevelDebug, fmt.Sprintf(format, a...))
+}
+
+// Tracef writes a formatted message at LevelTrace
+func (l *Logger) Tracef(format string, a ...interface{}) {
+	l.Log(LevelTrace, fmt.Sprintf(format, a...))
+}
+
+// Errorf writes a formatted message at LevelError with the Default Logger
+func Errorf(format string, a ...interface{}) {
+	Default.Errorf(format, a...)
+}
+
+// Warnf writes a formatted message at LevelWarn with the Default Logger
+func Warnf(format string, a ...interface{}) {
+	Default.Warnf(format, a...)
+}
+
+// Infof writes a formatted message at LevelInfo with the Default Logger
+func Infof(format string, a ...interface{}) {
+	Default.Infof(format, a...)
+}
+
+// Debugf writes a formatted message at LevelDebug with the Default Logger
+func Debugf(format string, a ...interface{}) {
+	Default.Debugf(format, a...)
+}
+
+// Tracef writes a formatted message at LevelTrace with the Default Logger
+func Tracef(format string, a ...interface{}) {
+	Default.Tracef(format, a...)
+}
@@ -0,0 +1,214 @@
+//
+// Copyright 2017-2021 Bryan T. Meyers <root@datadrake.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package options
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+)
+
+// ErrTooManyArgs indicates that too many arguments were provided for this subcommand
+var ErrTooManyArgs = errors.New("too many arguments")
+
+// ArgName gets the name of an argument, from its "name" tag or else its field name
+func ArgName(f reflect.StructField) string {
+	if name := f.Tag.Get("name"); name != "" {
+		return name
+	}
+	return f.Name
+}
+
+// ArgOptional checks if an argument may be left out, from its "optional" tag, or the "zero" tag of a slice
+//
+// An optional argument which is left out keeps its zero value, unless it has a "default" tag.
+func ArgOptional(f reflect.StructField) bool {
+	if f.Tag.Get("optional") != "" {
+		return true
+	}
+	return f.Type.Kind() == reflect.Slice && f.Tag.Get("zero") != ""
+}
+
+// ArgBounds gets the minimum and maximum number of values for a slice argument, from its "min" and "max" tags
+//
+// The minimum defaults to 1, or 0 for optional slices, and a maximum of 0 is unlimited.
+func ArgBounds(f reflect.StructField) (min, max int, err error) {
+	if !ArgOptional(f) {
+		min = 1
+	}
+	if raw, ok := f.Tag.Lookup("min"); ok {
+		if min, err = strconv.Atoi(raw); err != nil || min < 0 {
+			err = fmt.Errorf("invalid min for arg '%s': %s", ArgName(f), raw)
+			return
+		}
+	}
+	if raw, ok := f.Tag.Lookup("max"); ok {
+		if max, err = strconv.Atoi(raw); err != nil || max < 1 || max < min {
+			err = fmt.Errorf("invalid max for arg '%s': %s", ArgName(f), raw)
+		}
+	}
+	return
+}
+
+// argSpec describes a single field of an Args struct
+type argSpec struct {
+	name     string
+	slice    bool
+	optional bool
+	def      *string
+	min, max int
+}
+
+// verifyArgs checks that the fields of an Args struct can be filled in unambiguously
+func (p *Parser) verifyArgs(args interface{}) (out interface{}, err error) {
+	v := reflect.ValueOf(args)
+	if !v.IsValid() || v.IsZero() {
+		return
+	}
+	if problems := p.checkArgs(args); len(problems) > 0 {
+		err = errors.New(problems[0])
+		return
+	}
+	out = args
+	return
+}
+
+// ArgProblems checks an Args struct for mistakes in its definition, describing each one
+func ArgProblems(args interface{}) []string {
+	if v := reflect.ValueOf(args); !v.IsValid() || v.IsZero() {
+		return nil
+	}
+	return (&Parser{}).checkArgs(args)
+}
+
+// checkArgs describes every problem with the fields of an Args struct, while working out how to fill them in
+//
+// Required args must come before optional ones. There may be one slice, which can only be followed by required args.
+func (p *Parser) checkArgs(args interface{}) (problems []string) {
+	t := reflect.TypeOf(args)
+	if t.Kind() != reflect.Ptr || t.Elem().Kind() != reflect.Struct {
+		return []string{fmt.Sprintf("args must be a pointer to a struct, found: %s", t)}
+	}
+	t = t.Elem()
+	optional, slice := "", ""
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		spec := argSpec{
+			name:     ArgName(field),
+			slice:    field.Type.Kind() == reflect.Slice,
+			optional: ArgOptional(field),
+		}
+		elem := field.Type
+		if spec.slice {
+			elem = elem.Elem()
+		}
+		if kind := elem.Kind(); kind == reflect.Bool {
+			problems = append(problems, ErrBoolArg.Error()+", found: "+spec.name)
+		} else if !isScalar(kind) && !IsFile(elem) {
+			problems = append(problems, fmt.Sprintf("unsupported type for arg '%s': %s", spec.name, field.Type))
+		}
+		if def, ok := field.Tag.Lookup("default"); ok {
+			if !spec.optional || spec.slice {
+				problems = append(problems, fmt.Sprintf("only optional args may have a default, found: %s", spec.name))
+			}
+			spec.def = &def
+		}
+		if !spec.slice && (field.Tag.Get("min") != "" || field.Tag.Get("max") != "") {
+			problems = append(problems, fmt.Sprintf("only slice args may have a min or max, found: %s", spec.name))
+		}
+		switch {
+		case spec.slice && slice != "":
+			problems = append(problems, fmt.Sprintf("only one arg may be a slice, found: %s and %s", slice, spec.name))
+		case spec.slice:
+			var err error
+			if spec.min, spec.max, err = ArgBounds(field); err != nil {
+				problems = append(problems, err.Error())
+			}
+			slice = spec.name
+			p.minArgs += spec.min
+		case spec.optional && slice != "":
+			problems = append(problems, fmt.Sprintf("optional arg '%s' cannot follow slice arg '%s'", spec.name, slice))
+		case spec.optional:
+			optional = spec.name
+		case optional != "":
+			problems = append(problems, fmt.Sprintf("required arg '%s' cannot follow optional arg '%s'", spec.name, optional))
+		default:
+			p.minArgs++
+		}
+		p.args = append(p.args, spec)
+	}
+	return
+}
+
+// setArgs distributes the positional arguments over the fields of an Args struct
+//
+// Required args are always filled in, optional args are filled in from left to right with any remaining tokens, and
+// the slice receives the rest, up to its maximum.
+func (p *Parser) setArgs(args interface{}) error {
+	if len(p.tokens) < p.minArgs {
+		return ErrInsufficientArgs
+	}
+	if args == nil {
+		if len(p.tokens) > 0 {
+			return ErrTooManyArgs
+		}
+		return nil
+	}
+	v := reflect.ValueOf(args).Elem()
+	tokens := p.tokens
+	extra := len(tokens) - p.minArgs
+	for i, spec := range p.args {
+		field := v.Field(i)
+		if !field.CanSet() {
+			return fmt.Errorf("failed to set arg '%s', unsettable", spec.name)
+		}
+		var values []string
+		switch {
+		case spec.slice:
+			n := spec.min + extra
+			if spec.max > 0 && n > spec.max {
+				n = spec.max
+			}
+			extra -= n - spec.min
+			values, tokens = tokens[:n], tokens[n:]
+		case !spec.optional:
+			values, tokens = tokens[:1], tokens[1:]
+		case extra > 0:
+			values, tokens = tokens[:1], tokens[1:]
+			extra--
+		case spec.def != nil:
+			values = []string{*spec.def}
+		}
+		for _, value := range values {
+			if err := p.setArg(field, value); err != nil {
+				return fmt.Errorf("failed to parse arg '%s', reason: %s", spec.name, err)
+			}
+		}
+	}
+	if len(tokens) > 0 {
+		return ErrTooManyArgs
+	}
+	return nil
+}
+
+// setArg sets an arg, or appends to a slice arg, from a raw value
+func (p *Parser) setArg(field reflect.Value, value string) error {
+	p.value = &value
+	defer func() { p.value = nil }()
+	return p.setField(field, false, false)
+}
@@ -0,0 +1,66 @@
+//
+// Copyright 2017-2021 Bryan T. Meyers <root@datadrake.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package options
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// FlagDisplay formats the name of a flag as it would be typed, preferring its long name (e.g. "--verbose" or "-v")
+func FlagDisplay(name string) string {
+	if len(name) == 1 {
+		return "-" + name
+	}
+	return "--" + name
+}
+
+// Requires gets the names of the flags which must be provided alongside a flag, from its "requires" tag
+func Requires(tags reflect.StructTag) (names []string) {
+	for _, name := range strings.Split(tags.Get("requires"), ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	return
+}
+
+// checkConstraints makes sure that the flags provided satisfy their "xor" and "requires" tags
+func (p *Parser) checkConstraints() error {
+	groups := make(map[string]string)
+	for _, flags := range p.flags {
+		for _, flag := range Walk(flags) {
+			name := flag.Name()
+			if _, ok := p.sources[name]; !ok {
+				continue
+			}
+			if group := flag.Field.Tag.Get("xor"); group != "" {
+				if other, ok := groups[group]; ok {
+					return fmt.Errorf("flags '%s' and '%s' cannot be used together", FlagDisplay(other), FlagDisplay(name))
+				}
+				groups[group] = name
+			}
+			for _, required := range Requires(flag.Field.Tag) {
+				if _, ok := p.Source(required); !ok {
+					return fmt.Errorf("flag '%s' requires '%s'", FlagDisplay(name), FlagDisplay(required))
+				}
+			}
+		}
+	}
+	return nil
+}
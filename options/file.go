@@ -0,0 +1,154 @@
+//
+// Copyright 2017-2021 Bryan T. Meyers <root@datadrake.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package options
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+)
+
+// InputFile is a flag or arg which is opened for reading, where "-" means stdin
+type InputFile struct {
+	*os.File
+}
+
+// OutputFile is a flag or arg which is created or truncated for writing, where "-" means stdout
+type OutputFile struct {
+	*os.File
+}
+
+var (
+	fileType       = reflect.TypeOf((*os.File)(nil))
+	inputFileType  = reflect.TypeOf(InputFile{})
+	outputFileType = reflect.TypeOf(OutputFile{})
+)
+
+// IsFile checks if a flag or arg is opened by the parser, which is true for *os.File, InputFile, and OutputFile
+//
+// A plain *os.File is opened for reading, like an InputFile.
+func IsFile(t reflect.Type) bool {
+	return t == fileType || t == inputFileType || t == outputFileType
+}
+
+// pendingFile is a file to be opened once parsing has succeeded
+type pendingFile struct {
+	field reflect.Value
+	index int
+	path  string
+}
+
+// elemType gets the type of the file to be opened
+func (pf pendingFile) elemType() reflect.Type {
+	if pf.index >= 0 {
+		return pf.field.Type().Elem()
+	}
+	return pf.field.Type()
+}
+
+// addFile queues a file to be opened for a field, or for the element of a slice field at index
+func (p *Parser) addFile(field reflect.Value, index int, path string) {
+	p.pending = append(p.pending, pendingFile{field: field, index: index, path: path})
+}
+
+// openFiles opens every queued file, so that no files are created unless all of the arguments are valid
+//
+// Files for reading are opened first, so that no files are created if any of them are missing.
+func (p *Parser) openFiles() error {
+	pending := p.pending
+	p.pending = nil
+	sort.SliceStable(pending, func(i, j int) bool {
+		return pending[i].elemType() != outputFileType && pending[j].elemType() == outputFileType
+	})
+	for _, pf := range pending {
+		target := pf.field
+		if pf.index >= 0 {
+			target = target.Index(pf.index)
+		}
+		var file *os.File
+		var err error
+		if target.Type() == outputFileType {
+			file, err = p.createFile(pf.path)
+		} else {
+			file, err = p.openFile(pf.path)
+		}
+		if err != nil {
+			return err
+		}
+		switch target.Type() {
+		case inputFileType:
+			target.Set(reflect.ValueOf(InputFile{file}))
+		case outputFileType:
+			target.Set(reflect.ValueOf(OutputFile{file}))
+		default:
+			target.Set(reflect.ValueOf(file))
+		}
+	}
+	return nil
+}
+
+// openFile opens a file for reading, or returns stdin for "-"
+func (p *Parser) openFile(path string) (*os.File, error) {
+	if path == "-" {
+		return os.Stdin, nil
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read file '%s': %s", path, pathError(err))
+	}
+	if info, err := file.Stat(); err == nil && info.IsDir() {
+		file.Close()
+		return nil, fmt.Errorf("cannot read file '%s': is a directory", path)
+	}
+	p.files = append(p.files, file)
+	return file, nil
+}
+
+// createFile creates or truncates a file for writing, or returns stdout for "-"
+func (p *Parser) createFile(path string) (*os.File, error) {
+	if path == "-" {
+		return os.Stdout, nil
+	}
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot write file '%s': %s", path, pathError(err))
+	}
+	p.files = append(p.files, file)
+	return file, nil
+}
+
+// pathError strips the operation and path from an error, since they are already part of the message
+func pathError(err error) error {
+	var pe *os.PathError
+	if errors.As(err, &pe) {
+		return pe.Err
+	}
+	return err
+}
+
+// CloseFiles closes every file opened for a flag or arg, other than stdin and stdout, returning the first error
+func (p *Parser) CloseFiles() (err error) {
+	for _, file := range p.files {
+		if e := file.Close(); e != nil && err == nil {
+			err = e
+		}
+	}
+	p.files = nil
+	return
+}
@@ -19,6 +19,7 @@ package options
 import (
 	"errors"
 	"fmt"
+	"os"
 	"path/filepath"
 	"reflect"
 	"strconv"
@@ -27,10 +28,17 @@ import (
 
 // Parser can be used to read and convert the raw program arguments
 type Parser struct {
-	raw     List
-	numArgs int
-	maxArgs int
-	minArgs int
+	raw           List
+	extra         []interface{}
+	flags         []interface{}
+	sources       map[string]Source
+	value         *string
+	args          []argSpec
+	tokens        []string
+	minArgs       int
+	responseFiles bool
+	pending       []pendingFile
+	files         []*os.File
 }
 
 // NewParser does the initial parsing of arguments and returns the resulting Parser
@@ -41,67 +49,65 @@ func NewParser(args []string, single bool) (p *Parser, sub string) {
 	// pop subcommand off the front
 	sub, args = filepath.Base(args[0]), args[1:]
 	p = &Parser{
-		raw: NewList(args),
+		raw:     NewList(args),
+		sources: make(map[string]Source),
 	}
 	return
 }
 
+// AddFlags includes an additional struct of flags to be set when parsing, after the root and subcommand flags
+func (p *Parser) AddFlags(flags interface{}) {
+	p.extra = append(p.extra, flags)
+}
+
 // ErrInsufficientArgs indicates that not enough arguments were provided for this subcommand
 var ErrInsufficientArgs = errors.New("Missing argument(s)")
 
 // Parse processes arguments and sets flags and subcommand args as needed
 func (p *Parser) Parse(rFlags, cFlags, args interface{}) (err error) {
-	if rFlags, err = p.verifyFlags(rFlags); err != nil {
+	if p.responseFiles {
+		if p.raw.elements, err = ExpandResponseFiles(p.raw.elements); err != nil {
+			return
+		}
+	}
+	scope := append([]interface{}{rFlags, cFlags}, p.extra...)
+	if rFlags, err = p.verifyFlags(rFlags, scope); err != nil {
 		return
 	}
-	if cFlags, err = p.verifyFlags(cFlags); err != nil {
+	if cFlags, err = p.verifyFlags(cFlags, scope); err != nil {
 		return
 	}
+	for i, flags := range p.extra {
+		if p.extra[i], err = p.verifyFlags(flags, scope); err != nil {
+			return
+		}
+	}
 	if args, err = p.verifyArgs(args); err != nil {
 		return
 	}
+	p.flags = append([]interface{}{rFlags, cFlags}, p.extra...)
 	for !p.raw.IsEmpty() {
-		if err = p.parseArg(rFlags, cFlags, args); err != nil {
+		if err = p.parseArg(rFlags, cFlags); err != nil {
 			return
 		}
 	}
-	if p.numArgs < p.minArgs {
-		err = ErrInsufficientArgs
+	if err = p.setArgs(args); err != nil {
 		return
 	}
-	if !p.raw.IsEmpty() {
-		err = ErrTooManyArgs
+	if err = p.checkConstraints(); err != nil {
+		return
 	}
+	err = p.openFiles()
 	return
 }
 
-func (p *Parser) verifyFlags(flags interface{}) (out interface{}, err error) {
+func (p *Parser) verifyFlags(flags interface{}, scope []interface{}) (out interface{}, err error) {
 	if v := reflect.ValueOf(flags); v.IsValid() && !v.IsZero() {
-		t := v.Elem().Type()
-		for i := 0; i < t.NumField(); i++ {
-			if name := t.Field(i).Tag.Get("short"); len(name) > 1 {
-				err = fmt.Errorf("short flags must have one character names, found: %s", name)
-				return
-			}
-		}
-		out = flags
-	}
-	return
-}
-
-func (p *Parser) verifyArgs(args interface{}) (out interface{}, err error) {
-	if v := reflect.ValueOf(args); v.IsValid() && !v.IsZero() {
-		p.maxArgs = v.Elem().NumField()
-		p.minArgs = p.maxArgs
-		if p.maxArgs == 0 {
+		if problems := FlagProblems(flags, scope...); len(problems) > 0 {
+			err = errors.New(problems[0])
 			return
 		}
-		if last := v.Elem().Type().Field(p.maxArgs - 1); last.Type.Kind() == reflect.Slice {
-			if last.Tag.Get("zero") != "" {
-				p.minArgs = p.maxArgs - 1
-			}
-		}
-		out = args
+		out = flags
 	}
 	return
 }
@@ -109,24 +115,34 @@ func (p *Parser) verifyArgs(args interface{}) (out interface{}, err error) {
 // ErrMissingFlagName indicates that no flag name was provided
 var ErrMissingFlagName = errors.New("missing flag name")
 
-func (p *Parser) parseArg(rFlags, cFlags, args interface{}) error {
+func (p *Parser) parseArg(rFlags, cFlags interface{}) error {
 	arg := p.raw.Peek()
 	switch {
 	case strings.HasPrefix(arg, "--"):
 		return p.parseLongFlag(rFlags, cFlags)
-	case strings.HasPrefix(arg, "-"):
+	case strings.HasPrefix(arg, "-") && arg != "-":
 		return p.parseShortFlags(rFlags, cFlags)
 	default:
-		return p.setArg(args)
+		p.tokens = append(p.tokens, p.raw.Next())
+		return nil
 	}
 }
 
 func (p *Parser) parseLongFlag(rFlags, cFlags interface{}) error {
 	name := strings.TrimPrefix(p.raw.Next(), "--")
+	if i := strings.Index(name, "="); i >= 0 {
+		value := name[i+1:]
+		name, p.value = name[:i], &value
+		defer func() { p.value = nil }()
+	}
 	if len(name) == 0 {
 		return ErrMissingFlagName
 	}
-	return p.setAnyFlag(rFlags, cFlags, name, "long", true)
+	err := p.setAnyFlag(rFlags, cFlags, name, "long", true)
+	if err == nil && p.value != nil {
+		err = fmt.Errorf("flag '%s' does not accept a value", name)
+	}
+	return err
 }
 
 func (p *Parser) parseShortFlags(rFlags, cFlags interface{}) error {
@@ -145,64 +161,115 @@ func (p *Parser) parseShortFlags(rFlags, cFlags interface{}) error {
 
 // setAnyFlag attempts to set an entry in 'flags', using the unparsed arguments
 func (p *Parser) setAnyFlag(rFlags, cFlags interface{}, name, tag string, last bool) error {
-	if found, err := p.setFlag(rFlags, name, tag, last); found {
-		return err
+	all := append([]interface{}{rFlags, cFlags}, p.extra...)
+	for _, flags := range all {
+		if found, err := p.setFlag(flags, name, tag, last); found {
+			return err
+		}
 	}
-	if found, err := p.setFlag(cFlags, name, tag, last); found {
-		return err
+	if tag == "long" && strings.HasPrefix(name, "no-") {
+		for _, flags := range all {
+			if found, err := p.negateFlag(flags, strings.TrimPrefix(name, "no-")); found {
+				return err
+			}
+		}
 	}
-	return fmt.Errorf("invalid flag '%s'", name)
+	return &UnknownFlagError{Name: name, Long: tag == "long"}
 }
 
-func (p *Parser) setFlag(flags interface{}, name, tag string, last bool) (found bool, err error) {
-	// Try setting root flag
-	if flags == nil {
-		return
+// UnknownFlagError indicates that a flag was not found in any of the flag structs
+type UnknownFlagError struct {
+	Name string
+	Long bool
+}
+
+// Display formats the name of the flag as it was typed
+func (e *UnknownFlagError) Display() string {
+	if e.Long {
+		return "--" + e.Name
 	}
-	flagsElement := reflect.ValueOf(flags).Elem()
-	flagsType := flagsElement.Type()
-	// Iterate over struct fields
-	for i := 0; i < flagsType.NumField(); i++ {
-		element := flagsElement.Field(i)
-		if !element.CanSet() {
+	return "-" + e.Name
+}
+
+func (e *UnknownFlagError) Error() string {
+	return fmt.Sprintf("invalid flag '%s'", e.Name)
+}
+
+func (p *Parser) setFlag(flags interface{}, name, tag string, last bool) (found bool, err error) {
+	for _, flag := range Walk(flags) {
+		if !flag.Value.CanSet() {
 			continue
 		}
-		if tags := flagsType.Field(i).Tag; name == tags.Get(tag) {
+		if (tag == "short" && name == flag.Short) || (tag == "long" && flag.HasLong(name)) {
 			found = true
-			err = p.setField(element, true, last)
+			p.sources[flag.Name()] = SourceCLI
+			if flag.Field.Tag.Get("count") != "" {
+				countField(settable(flag.Value))
+				return
+			}
+			err = p.setField(settable(flag.Value), true, last)
 			return
 		}
 	}
 	return
 }
 
-// ErrTooManyArgs indicates that too many arguments were provided for this subcommand
-var ErrTooManyArgs = errors.New("too many arguments")
-
-// setArg attempts to set an entry in 'args', using an unparsed argument
-func (p *Parser) setArg(args interface{}) error {
-	if args == nil {
-		return ErrTooManyArgs
-	}
-	argsElement := reflect.ValueOf(args).Elem()
-	if p.numArgs >= p.maxArgs {
-		arg := argsElement.Field(p.maxArgs - 1)
-		if arg.Kind() != reflect.Slice {
-			return ErrTooManyArgs
+// negateFlag attempts to turn off a negatable bool flag in 'flags', by its long name
+func (p *Parser) negateFlag(flags interface{}, name string) (found bool, err error) {
+	for _, flag := range Walk(flags) {
+		if !flag.Value.CanSet() || flag.Field.Tag.Get("negate") == "" || !flag.HasLong(name) {
+			continue
 		}
-		p.numArgs++
-		p.appendSlice(arg)
-		return nil
+		found = true
+		if p.value != nil {
+			err = fmt.Errorf("flag 'no-%s' does not accept a value", name)
+			return
+		}
+		p.sources[flag.Name()] = SourceCLI
+		settable(flag.Value).SetBool(false)
+		return
 	}
-	arg := argsElement.Field(p.numArgs)
-	if !arg.CanSet() {
-		return fmt.Errorf("Failed to set arg '%s', unsettable", arg)
+	return
+}
+
+// fieldKind gets the Kind of a field, or of the value it points to
+func fieldKind(t reflect.Type) reflect.Kind {
+	if t.Kind() == reflect.Ptr {
+		return t.Elem().Kind()
 	}
-	if err := p.setField(arg, false, false); err != nil {
-		return fmt.Errorf("Failed to parse arg '%s', reason: %s", arg, err)
+	return t.Kind()
+}
+
+// settable gets the value to set for a field, allocating it first if the field is a nil pointer (other than a file)
+func settable(field reflect.Value) reflect.Value {
+	if field.Kind() != reflect.Ptr || IsFile(field.Type()) {
+		return field
+	}
+	if field.IsNil() {
+		field.Set(reflect.New(field.Type().Elem()))
+	}
+	return field.Elem()
+}
+
+// isInteger checks if a Kind is a signed or unsigned integer
+func isInteger(k reflect.Kind) bool {
+	switch k {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return true
+	}
+	return false
+}
+
+// countField increments a counted flag
+func countField(field reflect.Value) {
+	switch field.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		field.SetInt(field.Int() + 1)
+	default:
+		field.SetUint(field.Uint() + 1)
 	}
-	p.numArgs++
-	return nil
 }
 
 // ErrMissingValue indicates that a flag does not have an associated value
@@ -224,12 +291,21 @@ func (p *Parser) setField(field reflect.Value, flag, last bool) error {
 		if flag {
 			return ErrSliceFlag
 		}
-		p.appendSlice(field)
+		return p.appendSlice(field)
 	case reflect.Bool:
 		if !flag {
 			return ErrBoolArg
 		}
-		field.SetBool(true)
+		if p.value == nil {
+			field.SetBool(true)
+			return nil
+		}
+		value, err := parseBool(*p.value)
+		p.value = nil
+		if err != nil {
+			return err
+		}
+		field.SetBool(value)
 	default:
 		if flag && !last {
 			return ErrBadGroup
@@ -239,13 +315,42 @@ func (p *Parser) setField(field reflect.Value, flag, last bool) error {
 	return nil
 }
 
-func (p *Parser) setFieldValue(field reflect.Value) error {
+// parseBool converts the explicit value of a bool flag
+func parseBool(raw string) (bool, error) {
+	switch strings.ToLower(raw) {
+	case "true", "yes", "1":
+		return true, nil
+	case "false", "no", "0":
+		return false, nil
+	}
+	return false, fmt.Errorf("'%s' is not a valid bool, expected true, false, yes, no, 1, or 0", raw)
+}
+
+// nextValue gets the value for a flag or arg, either from "--flag=value" or the next unparsed argument
+func (p *Parser) nextValue() (raw string, err error) {
+	if p.value != nil {
+		raw, p.value = *p.value, nil
+		return
+	}
 	if p.raw.IsEmpty() {
-		return ErrMissingValue
+		err = ErrMissingValue
+		return
+	}
+	raw = p.raw.Next()
+	if raw == "" {
+		err = ErrMissingValue
+	}
+	return
+}
+
+func (p *Parser) setFieldValue(field reflect.Value) error {
+	raw, err := p.nextValue()
+	if err != nil {
+		return err
 	}
-	raw := p.raw.Next()
-	if strings.HasPrefix("-", raw) {
-		return ErrMissingValue
+	if IsFile(field.Type()) {
+		p.addFile(field, -1, raw)
+		return nil
 	}
 	switch field.Kind() {
 	case reflect.String:
@@ -274,8 +379,21 @@ func (p *Parser) setFieldValue(field reflect.Value) error {
 	return nil
 }
 
-func (p *Parser) appendSlice(field reflect.Value) {
+func (p *Parser) appendSlice(field reflect.Value) error {
+	if IsFile(field.Type().Elem()) {
+		// files are opened later, so the element is found by its index in case the slice is reallocated
+		raw, err := p.nextValue()
+		if err != nil {
+			return err
+		}
+		p.addFile(field, field.Len(), raw)
+		field.Set(reflect.Append(field, reflect.Zero(field.Type().Elem())))
+		return nil
+	}
 	elem := reflect.New(field.Type().Elem())
-	p.setFieldValue(elem.Elem())
+	if err := p.setFieldValue(elem.Elem()); err != nil {
+		return err
+	}
 	field.Set(reflect.Append(field, elem.Elem()))
+	return nil
 }
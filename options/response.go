@@ -0,0 +1,143 @@
+//
+// Copyright 2017-2021 Bryan T. Meyers <root@datadrake.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package options
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode"
+)
+
+// MaxResponseDepth is the number of response files which may be nested inside of one another
+const MaxResponseDepth = 8
+
+// EnableResponseFiles causes Parse to replace any "@path" argument with the arguments listed in that file
+//
+// Arguments in a response file are separated by whitespace and may be quoted or escaped like in a shell. A '#' at the
+// start of an argument begins a comment, which runs until the end of the line. "@-" reads arguments from stdin.
+//
+// Relative paths in a response file are resolved from the directory of that file, rather than the working directory.
+func (p *Parser) EnableResponseFiles() {
+	p.responseFiles = true
+}
+
+// ExpandResponseFiles replaces any "@path" arguments with the arguments listed in those files, recursively
+func ExpandResponseFiles(args []string) ([]string, error) {
+	return expandResponseFiles(args, "", 0)
+}
+
+// expandResponseFiles expands the arguments of a single response file, found in dir, or of the command line
+func expandResponseFiles(args []string, dir string, depth int) (out []string, err error) {
+	for _, arg := range args {
+		if len(arg) < 2 || arg[0] != '@' {
+			out = append(out, arg)
+			continue
+		}
+		path, base := arg[1:], dir
+		if path != "-" && dir != "" && !filepath.IsAbs(path) {
+			path = filepath.Join(dir, path)
+		}
+		if depth == MaxResponseDepth {
+			return nil, fmt.Errorf("response file '%s' is nested more than %d deep", path, MaxResponseDepth)
+		}
+		var raw []byte
+		if path == "-" {
+			raw, err = ioutil.ReadAll(os.Stdin)
+		} else {
+			raw, err = ioutil.ReadFile(path)
+			base = filepath.Dir(path)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read response file: %s", err)
+		}
+		inner, err := splitResponse(string(raw))
+		if err != nil {
+			return nil, fmt.Errorf("response file '%s': %s", path, err)
+		}
+		if inner, err = expandResponseFiles(inner, base, depth+1); err != nil {
+			return nil, err
+		}
+		out = append(out, inner...)
+	}
+	return
+}
+
+// splitResponse tokenizes the contents of a response file, following the quoting rules of a POSIX shell
+func splitResponse(raw string) (args []string, err error) {
+	var arg strings.Builder
+	var quote rune
+	inArg, escaped, comment := false, false, false
+	for _, c := range raw {
+		switch {
+		case comment:
+			comment = c != '\n'
+		case escaped:
+			// a backslash-newline is a line continuation, and in double quotes only a few characters are escaped
+			if quote == '"' && !strings.ContainsRune("\"\\$`\n", c) {
+				arg.WriteRune('\\')
+			}
+			if c != '\n' {
+				arg.WriteRune(c)
+				inArg = true
+			}
+			escaped = false
+		case quote == '\'':
+			if c == '\'' {
+				quote = 0
+			} else {
+				arg.WriteRune(c)
+			}
+		case quote == '"':
+			switch c {
+			case '"':
+				quote = 0
+			case '\\':
+				escaped = true
+			default:
+				arg.WriteRune(c)
+			}
+		case c == '\'' || c == '"':
+			quote, inArg = c, true
+		case c == '\\':
+			escaped = true
+		case unicode.IsSpace(c):
+			if inArg {
+				args = append(args, arg.String())
+				arg.Reset()
+				inArg = false
+			}
+		case c == '#' && !inArg:
+			comment = true
+		default:
+			arg.WriteRune(c)
+			inArg = true
+		}
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("missing closing quote %c", quote)
+	}
+	if escaped {
+		return nil, fmt.Errorf("unexpected backslash at end of file")
+	}
+	if inArg {
+		args = append(args, arg.String())
+	}
+	return
+}
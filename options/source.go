@@ -0,0 +1,111 @@
+//
+// Copyright 2017-2021 Bryan T. Meyers <root@datadrake.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package options
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Source is where the value of a flag came from, in increasing order of precedence
+type Source int
+
+const (
+	// SourceDefault is a default value provided by the program
+	SourceDefault Source = iota
+	// SourceConfig is a value read from a configuration file
+	SourceConfig
+	// SourceEnv is a value read from an environment variable
+	SourceEnv
+	// SourceCLI is a value provided in the program arguments
+	SourceCLI
+)
+
+// sourceNames are the names of each Source
+var sourceNames = []string{"default", "config", "env", "cli"}
+
+// String returns the name of this Source
+func (s Source) String() string {
+	if s < SourceDefault || int(s) >= len(sourceNames) {
+		return fmt.Sprintf("source(%d)", int(s))
+	}
+	return sourceNames[s]
+}
+
+// Provided returns the name of every flag that has been set, along with where its value came from
+func (p *Parser) Provided() map[string]Source {
+	provided := make(map[string]Source, len(p.sources))
+	for name, src := range p.sources {
+		provided[name] = src
+	}
+	return provided
+}
+
+// Source gets where the value of a flag came from, by its long or short name
+//
+// If the flag has not been set, ok is false.
+func (p *Parser) Source(name string) (src Source, ok bool) {
+	if flag, found := p.findFlag(name); found {
+		src, ok = p.sources[flag.Name()]
+	}
+	return
+}
+
+// Set sets a flag from a raw value, by its long or short name, unless it was already set from a higher precedence Source
+//
+// Set is intended for layering values from sources other than the program arguments, once they have been parsed.
+func (p *Parser) Set(name, value string, src Source) error {
+	flag, found := p.findFlag(name)
+	if !found {
+		return fmt.Errorf("invalid flag '%s'", name)
+	}
+	field, key := flag.Value, flag.Name()
+	if prev, ok := p.sources[key]; ok && prev > src {
+		return nil
+	}
+	// pointers are only replaced on success, so that they stay nil if the value is invalid
+	target := field
+	if field.Kind() == reflect.Ptr && !IsFile(field.Type()) {
+		target = reflect.New(field.Type().Elem()).Elem()
+	}
+	p.value = &value
+	defer func() { p.value = nil }()
+	if err := p.setField(target, true, true); err != nil {
+		return fmt.Errorf("failed to set flag '%s', reason: %s", name, err)
+	}
+	if err := p.openFiles(); err != nil {
+		return fmt.Errorf("failed to set flag '%s', reason: %s", name, err)
+	}
+	if field.Kind() == reflect.Ptr && !IsFile(field.Type()) {
+		field.Set(target.Addr())
+	}
+	p.sources[key] = src
+	return nil
+}
+
+// findFlag searches the parsed flags for a flag with the specified long or short name
+func (p *Parser) findFlag(name string) (flag Flag, found bool) {
+	for _, flags := range p.flags {
+		for _, flag = range Walk(flags) {
+			if (flag.HasLong(name) || name == flag.Short) && flag.Value.CanSet() {
+				found = true
+				return
+			}
+		}
+	}
+	return
+}
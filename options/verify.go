@@ -0,0 +1,106 @@
+//
+// Copyright 2017-2021 Bryan T. Meyers <root@datadrake.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package options
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// isScalar checks if a Kind can be parsed from a single argument
+func isScalar(k reflect.Kind) bool {
+	switch k {
+	case reflect.Bool, reflect.String, reflect.Float32, reflect.Float64:
+		return true
+	}
+	return isInteger(k)
+}
+
+// FlagProblems checks a struct of flags for mistakes in its definition, describing each one
+//
+// The "requires" and "xor" tags are checked against every struct of flags in scope, which are all of the flags that may
+// be used alongside these (e.g. global flags), or only these flags if the scope is empty.
+func FlagProblems(flags interface{}, scope ...interface{}) (problems []string) {
+	if v := reflect.ValueOf(flags); !v.IsValid() || v.IsZero() {
+		return
+	}
+	t := reflect.TypeOf(flags)
+	if t.Kind() != reflect.Ptr || t.Elem().Kind() != reflect.Struct {
+		return []string{fmt.Sprintf("flags must be a pointer to a struct, found: %s", t)}
+	}
+	for _, flag := range Walk(flags) {
+		name := flag.Field.Name
+		if flag.Short == "" && flag.Long == "" {
+			problems = append(problems, fmt.Sprintf("flags must have a short or long name, found: %s", name))
+		}
+		if len(flag.Short) > 1 {
+			problems = append(problems, fmt.Sprintf("short flags must have one character names, found: %s (%s)", name, flag.Short))
+		}
+		kind := fieldKind(flag.Field.Type)
+		switch {
+		case kind == reflect.Slice:
+			problems = append(problems, fmt.Sprintf("%s, found: %s", ErrSliceFlag, name))
+		case !isScalar(kind) && !IsFile(flag.Field.Type):
+			problems = append(problems, fmt.Sprintf("unsupported type for flag '%s': %s", name, flag.Field.Type))
+		}
+		if flag.Field.Tag.Get("count") != "" && !isInteger(kind) {
+			problems = append(problems, fmt.Sprintf("counted flags must be integers, found: %s (%s)", name, kind))
+		}
+		if flag.Field.Tag.Get("negate") != "" && (kind != reflect.Bool || flag.Long == "") {
+			problems = append(problems, fmt.Sprintf("negatable flags must be bools with a long name, found: %s", name))
+		}
+	}
+	if len(scope) == 0 {
+		scope = []interface{}{flags}
+	}
+	return append(problems, constraintProblems(flags, scope)...)
+}
+
+// constraintProblems checks that every "requires" tag names a flag in scope, and that every "xor" group has more than
+// one flag in scope
+func constraintProblems(flags interface{}, scope []interface{}) (problems []string) {
+	names := make(map[string]bool)
+	members := make(map[string]int)
+	for _, set := range scope {
+		if t := reflect.TypeOf(set); t == nil || t.Kind() != reflect.Ptr || t.Elem().Kind() != reflect.Struct {
+			continue
+		}
+		for _, flag := range Walk(set) {
+			names[flag.Short] = true
+			names[flag.Long] = true
+			for _, alias := range flag.Aliases {
+				names[alias] = true
+			}
+			if group := flag.Field.Tag.Get("xor"); group != "" {
+				members[group]++
+			}
+		}
+	}
+	delete(names, "")
+	for _, flag := range Walk(flags) {
+		name := flag.Field.Name
+		for _, required := range Requires(flag.Field.Tag) {
+			if !names[required] {
+				problems = append(problems, fmt.Sprintf("flags must only require other flags, found: %s (%s)", name, required))
+			}
+		}
+		if group := flag.Field.Tag.Get("xor"); group != "" && members[group] < 2 {
+			problems = append(problems, fmt.Sprintf("xor groups must have more than one flag, found: %s (%s)", name, group))
+		}
+	}
+	return
+}
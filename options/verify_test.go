@@ -0,0 +1,77 @@
+//
+// Copyright 2017-2021 Bryan T. Meyers <root@datadrake.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package options
+
+import (
+	"strings"
+	"testing"
+)
+
+type loginFlags struct {
+	User string `long:"user" requires:"password" desc:"User"`
+}
+
+type passwordFlags struct {
+	Password string `short:"p" long:"password" desc:"Password"`
+}
+
+type typoFlags struct {
+	User     string `long:"user" requires:"pasword" desc:"User"`
+	Password string `long:"password" desc:"Password"`
+}
+
+type jsonFlags struct {
+	JSON bool `long:"json" xor:"format" desc:"JSON"`
+}
+
+type tableFlags struct {
+	Table bool `long:"table" xor:"format" desc:"Table"`
+}
+
+func expectProblem(t *testing.T, problems []string, want string) {
+	t.Helper()
+	for _, problem := range problems {
+		if strings.Contains(problem, want) {
+			return
+		}
+	}
+	t.Errorf("expected a problem containing %q, found: %q", want, problems)
+}
+
+func TestFlagProblemsRequires(t *testing.T) {
+	expectProblem(t, FlagProblems(&typoFlags{}), "found: User (pasword)")
+	expectProblem(t, FlagProblems(&loginFlags{}), "found: User (password)")
+	login, password := &loginFlags{}, &passwordFlags{}
+	if problems := FlagProblems(login, login, password); len(problems) > 0 {
+		t.Errorf("unexpected problems: %q", problems)
+	}
+}
+
+func TestFlagProblemsXor(t *testing.T) {
+	expectProblem(t, FlagProblems(&jsonFlags{}), "found: JSON (format)")
+	json, table := &jsonFlags{}, &tableFlags{}
+	if problems := FlagProblems(json, json, table); len(problems) > 0 {
+		t.Errorf("unexpected problems: %q", problems)
+	}
+}
+
+func TestParseRejectsUnknownRequires(t *testing.T) {
+	p, _ := NewParser([]string{"sub", "--user", "me"}, false)
+	if err := p.Parse(nil, &typoFlags{}, nil); err == nil || !strings.Contains(err.Error(), "pasword") {
+		t.Errorf("expected an error for the unknown required flag, found: %v", err)
+	}
+}
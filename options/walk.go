@@ -0,0 +1,107 @@
+//
+// Copyright 2017-2021 Bryan T. Meyers <root@datadrake.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package options
+
+import (
+	"reflect"
+	"strings"
+)
+
+// Flag is a single flag, found by walking a struct of flags along with any embedded or nested structs
+type Flag struct {
+	Field   reflect.StructField
+	Value   reflect.Value
+	Short   string
+	Long    string
+	Aliases []string
+	Group   string
+}
+
+// Name identifies a flag by its long name, or else its short name
+func (f Flag) Name() string {
+	if f.Long != "" {
+		return f.Long
+	}
+	return f.Short
+}
+
+// HasLong checks if a flag can be set by the specified long name, either its primary name or one of its aliases
+func (f Flag) HasLong(name string) bool {
+	if name == f.Long {
+		return name != ""
+	}
+	for _, alias := range f.Aliases {
+		if name == alias {
+			return true
+		}
+	}
+	return false
+}
+
+// isGroup checks if a field is a struct of more flags, rather than a flag itself
+func isGroup(f reflect.StructField) bool {
+	return f.Type.Kind() == reflect.Struct && f.Tag.Get("short") == "" && f.Tag.Get("long") == ""
+}
+
+// Walk flattens a pointer to a struct of flags, including the fields of any embedded or nested structs
+//
+// The fields of embedded structs are treated as if they belonged to the outer struct. The long names of flags in a
+// nested struct are prefixed by its "prefix" tag, and the flags are grouped under its "group" tag or field name. A
+// "long" tag may list several comma-separated names, where the first is the primary name and the rest are aliases.
+func Walk(flags interface{}) []Flag {
+	v := reflect.ValueOf(flags)
+	if !v.IsValid() || v.IsZero() {
+		return nil
+	}
+	return walk(v.Elem(), "", "")
+}
+
+func walk(v reflect.Value, prefix, group string) (all []Flag) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if isGroup(field) {
+			if field.Anonymous {
+				all = append(all, walk(v.Field(i), prefix, group)...)
+				continue
+			}
+			title := field.Tag.Get("group")
+			if title == "" {
+				title = field.Name
+			}
+			all = append(all, walk(v.Field(i), prefix+field.Tag.Get("prefix"), title)...)
+			continue
+		}
+		flag := Flag{
+			Field: field,
+			Value: v.Field(i),
+			Short: field.Tag.Get("short"),
+			Group: group,
+		}
+		if long := field.Tag.Get("long"); long != "" {
+			for i, name := range strings.Split(long, ",") {
+				if name = prefix + strings.TrimSpace(name); i == 0 {
+					flag.Long = name
+				} else {
+					flag.Aliases = append(flag.Aliases, name)
+				}
+			}
+		}
+		all = append(all, flag)
+	}
+	return
+}
@@ -0,0 +1,112 @@
+//
+// Copyright 2017-2021 Bryan T. Meyers <root@datadrake.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package output
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/template"
+)
+
+// Format is a way of rendering the results of a command
+type Format string
+
+const (
+	// Table renders results as an aligned table, with one row per result
+	Table Format = "table"
+	// JSON renders results as an indented JSON array
+	JSON Format = "json"
+	// JSONLines renders results as one JSON object per line
+	JSONLines Format = "jsonl"
+	// YAML renders results as a YAML sequence
+	YAML Format = "yaml"
+	// CSV renders results as comma-separated values, with a header row
+	CSV Format = "csv"
+	// Template renders each result with a Go template (e.g. template='{{.Name}}')
+	Template Format = "template"
+)
+
+// Formats are all of the supported Formats, in the order they should be listed
+var Formats = []Format{Table, JSON, JSONLines, YAML, CSV, Template}
+
+// Spec is a parsed output specification, as provided to the --output flag
+type Spec struct {
+	Format   Format
+	Template *template.Template
+}
+
+// ParseSpec parses an output specification, which is the name of a Format or "template=" followed by a Go template
+func ParseSpec(raw string) (spec Spec, err error) {
+	if raw == "" {
+		spec.Format = Table
+		return
+	}
+	name, text := raw, ""
+	if i := strings.Index(raw, "="); i >= 0 {
+		name, text = raw[:i], raw[i+1:]
+	}
+	spec.Format = Format(name)
+	switch spec.Format {
+	case Table, JSON, JSONLines, YAML, CSV:
+		if i := strings.Index(raw, "="); i >= 0 {
+			err = fmt.Errorf("output format '%s' does not accept an argument", name)
+		}
+	case Template:
+		if text == "" {
+			err = fmt.Errorf("output format 'template' requires a template (e.g. template='{{.Name}}')")
+			return
+		}
+		spec.Template, err = template.New("output").Parse(text)
+	default:
+		err = fmt.Errorf("unknown output format '%s'", name)
+	}
+	return
+}
+
+// Write renders data to w, according to an output specification
+//
+// Data may be a struct, a pointer to a struct, or a slice of either.
+func Write(w io.Writer, raw string, data interface{}) error {
+	spec, err := ParseSpec(raw)
+	if err != nil {
+		return err
+	}
+	return spec.Write(w, data)
+}
+
+// Write renders data to w in the format of this Spec
+func (spec Spec) Write(w io.Writer, data interface{}) error {
+	records, err := newRecords(data)
+	if err != nil {
+		return err
+	}
+	switch spec.Format {
+	case JSON:
+		return writeJSON(w, records)
+	case JSONLines:
+		return writeJSONLines(w, records)
+	case YAML:
+		return writeYAML(w, records)
+	case CSV:
+		return writeCSV(w, records)
+	case Template:
+		return writeTemplate(w, spec.Template, records)
+	default:
+		return writeTable(w, records)
+	}
+}
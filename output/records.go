@@ -0,0 +1,114 @@
+//
+// Copyright 2017-2021 Bryan T. Meyers <root@datadrake.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package output
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// column is a single exported field of a result
+type column struct {
+	Name  string
+	Index int
+}
+
+// records are the results of a command, broken down into named columns
+type records struct {
+	columns []column
+	values  []reflect.Value
+}
+
+// newRecords breaks down a struct, or a slice of structs, into records
+func newRecords(data interface{}) (r records, err error) {
+	v := indirect(reflect.ValueOf(data))
+	if !v.IsValid() {
+		err = fmt.Errorf("output must be a struct or a slice of structs, found: nil")
+		return
+	}
+	t := v.Type()
+	switch v.Kind() {
+	case reflect.Slice, reflect.Array:
+		t = t.Elem()
+		for i := 0; i < v.Len(); i++ {
+			r.values = append(r.values, indirect(v.Index(i)))
+		}
+	case reflect.Struct:
+		r.values = append(r.values, v)
+	}
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		err = fmt.Errorf("output must be a struct or a slice of structs, found: %s", v.Type())
+		return
+	}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+		if name := columnName(field); name != "-" {
+			r.columns = append(r.columns, column{name, i})
+		}
+	}
+	return
+}
+
+// indirect follows pointers until it finds a value, which is invalid for nil pointers
+func indirect(v reflect.Value) reflect.Value {
+	for v.IsValid() && (v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface) {
+		if v.IsNil() {
+			return reflect.Value{}
+		}
+		v = v.Elem()
+	}
+	return v
+}
+
+// columnName gets the name of a field from its "output" or "json" tag, falling back to the field name
+//
+// A name of "-" means that the field should be skipped.
+func columnName(field reflect.StructField) string {
+	if name := field.Tag.Get("output"); name != "" {
+		return name
+	}
+	if name := strings.Split(field.Tag.Get("json"), ",")[0]; name != "" {
+		return name
+	}
+	return field.Name
+}
+
+// value gets the value of a column for a single record, which is nil for nil pointers
+func (r records) value(record reflect.Value, c column) interface{} {
+	if !record.IsValid() {
+		return nil
+	}
+	if v := indirect(record.Field(c.Index)); v.IsValid() {
+		return v.Interface()
+	}
+	return nil
+}
+
+// text gets the value of a column for a single record as plain text
+func (r records) text(record reflect.Value, c column) string {
+	if v := r.value(record, c); v != nil {
+		return fmt.Sprint(v)
+	}
+	return ""
+}
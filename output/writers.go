@@ -0,0 +1,187 @@
+//
+// Copyright 2017-2021 Bryan T. Meyers <root@datadrake.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package output
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"github.com/DataDrake/cli-ng/v2/term"
+	"io"
+	"reflect"
+	"regexp"
+	"strings"
+	"text/template"
+)
+
+func writeTable(w io.Writer, r records) error {
+	var headers []string
+	for _, c := range r.columns {
+		headers = append(headers, strings.ToUpper(c.Name))
+	}
+	t := term.NewTable(headers...)
+	t.Width = term.Width()
+	t.HeaderStyle = term.CurrentTheme().TableHeader
+	for _, record := range r.values {
+		var cells []string
+		for _, c := range r.columns {
+			cells = append(cells, r.text(record, c))
+		}
+		t.AddRow(cells...)
+	}
+	return t.Render(w)
+}
+
+// object encodes a single record as a compact JSON object, with keys in the order of the columns
+func (r records) object(record reflect.Value) (json.RawMessage, error) {
+	var b bytes.Buffer
+	b.WriteByte('{')
+	for i, c := range r.columns {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		key, _ := json.Marshal(c.Name)
+		value, err := json.Marshal(r.value(record, c))
+		if err != nil {
+			return nil, err
+		}
+		b.Write(key)
+		b.WriteByte(':')
+		b.Write(value)
+	}
+	b.WriteByte('}')
+	return b.Bytes(), nil
+}
+
+func writeJSON(w io.Writer, r records) error {
+	objects := make([]json.RawMessage, 0, len(r.values))
+	for _, record := range r.values {
+		object, err := r.object(record)
+		if err != nil {
+			return err
+		}
+		objects = append(objects, object)
+	}
+	out, err := json.MarshalIndent(objects, "", "    ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "%s\n", out)
+	return err
+}
+
+func writeJSONLines(w io.Writer, r records) error {
+	for _, record := range r.values {
+		object, err := r.object(record)
+		if err != nil {
+			return err
+		}
+		if _, err = fmt.Fprintf(w, "%s\n", object); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// plainYAML matches strings which do not need to be quoted in YAML
+var plainYAML = regexp.MustCompile(`^[A-Za-z_/][A-Za-z0-9 _./@-]*[A-Za-z0-9_./@-]$|^[A-Za-z_/]$`)
+
+// reservedYAML are plain strings which YAML would interpret as something other than a string
+var reservedYAML = map[string]bool{
+	"true": true, "false": true, "yes": true, "no": true, "on": true, "off": true,
+	"null": true, "y": true, "n": true, "~": true,
+}
+
+// yamlValue encodes a single value as YAML, using JSON flow style for anything other than plain strings
+func yamlValue(v interface{}) (string, error) {
+	if s, ok := v.(string); ok && plainYAML.MatchString(s) && !reservedYAML[strings.ToLower(s)] {
+		return s, nil
+	}
+	out, err := json.Marshal(v)
+	return string(out), err
+}
+
+func writeYAML(w io.Writer, r records) error {
+	if len(r.values) == 0 {
+		_, err := io.WriteString(w, "[]\n")
+		return err
+	}
+	for _, record := range r.values {
+		if len(r.columns) == 0 {
+			if _, err := io.WriteString(w, "- {}\n"); err != nil {
+				return err
+			}
+		}
+		for i, c := range r.columns {
+			value, err := yamlValue(r.value(record, c))
+			if err != nil {
+				return err
+			}
+			key, err := yamlValue(c.Name)
+			if err != nil {
+				return err
+			}
+			prefix := "  "
+			if i == 0 {
+				prefix = "- "
+			}
+			if _, err = fmt.Fprintf(w, "%s%s: %s\n", prefix, key, value); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func writeCSV(w io.Writer, r records) error {
+	out := csv.NewWriter(w)
+	var headers []string
+	for _, c := range r.columns {
+		headers = append(headers, c.Name)
+	}
+	if err := out.Write(headers); err != nil {
+		return err
+	}
+	for _, record := range r.values {
+		var cells []string
+		for _, c := range r.columns {
+			cells = append(cells, r.text(record, c))
+		}
+		if err := out.Write(cells); err != nil {
+			return err
+		}
+	}
+	out.Flush()
+	return out.Error()
+}
+
+func writeTemplate(w io.Writer, tmpl *template.Template, r records) error {
+	for _, record := range r.values {
+		var data interface{}
+		if record.IsValid() {
+			data = record.Interface()
+		}
+		if err := tmpl.Execute(w, data); err != nil {
+			return err
+		}
+		if _, err := io.WriteString(w, "\n"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
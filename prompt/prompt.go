@@ -0,0 +1,268 @@
+//
+// Copyright 2017-2021 Bryan T. Meyers <root@datadrake.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package prompt
+
+import (
+	"errors"
+	"fmt"
+	"github.com/DataDrake/cli-ng/v2/term"
+	"io"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+)
+
+// AssumeYes causes every prompt to immediately accept its default, and Confirm to answer yes
+var AssumeYes bool
+
+// In is where answers are read from
+var In = os.Stdin
+
+// Out is where questions are written to
+var Out io.Writer = os.Stderr
+
+// ErrNotInteractive indicates that a prompt needs an answer, but prompting is disabled or In is not a terminal
+var ErrNotInteractive = errors.New("cannot prompt for input, prompts are disabled or stdin is not a terminal")
+
+// ErrNoEcho indicates that Password cannot hide what is typed, because this platform does not support it
+var ErrNoEcho = errors.New("cannot hide typed input, disabling echo is not supported on this platform")
+
+// ErrInterrupted indicates that a prompt was cancelled with Ctrl-C
+var ErrInterrupted = errors.New("interrupted")
+
+// interactive checks if prompts should wait for an answer
+func interactive() bool {
+	return !AssumeYes && term.IsTerminal(In)
+}
+
+// question formats a question, with an optional hint of the expected answers
+func question(q, hint string) string {
+	if hint != "" {
+		q += " " + hint
+	}
+	return term.Style{}.Bold().SprintFor(Out, q) + " "
+}
+
+// readLine reads a single line of input without buffering, so that no input is lost between prompts
+func readLine() (string, error) {
+	var line []byte
+	b := make([]byte, 1)
+	for {
+		n, err := In.Read(b)
+		if n > 0 {
+			if b[0] == '\n' {
+				break
+			}
+			line = append(line, b[0])
+		}
+		if err == io.EOF {
+			if len(line) == 0 {
+				return "", err
+			}
+			break
+		}
+		if err != nil {
+			return "", err
+		}
+	}
+	return strings.TrimRight(string(line), "\r"), nil
+}
+
+// Confirm asks a yes/no question, returning def if no answer is given
+func Confirm(q string, def bool) (bool, error) {
+	if AssumeYes {
+		return true, nil
+	}
+	if !interactive() {
+		return def, nil
+	}
+	hint := "[y/N]"
+	if def {
+		hint = "[Y/n]"
+	}
+	for {
+		fmt.Fprint(Out, question(q, hint))
+		answer, err := readLine()
+		if err != nil {
+			return def, err
+		}
+		switch strings.ToLower(strings.TrimSpace(answer)) {
+		case "":
+			return def, nil
+		case "y", "yes":
+			return true, nil
+		case "n", "no":
+			return false, nil
+		}
+		fmt.Fprintln(Out, "Please answer 'y' or 'n'.")
+	}
+}
+
+// Input asks for a line of text, returning def if no answer is given
+//
+// If validate is not nil, the question is repeated until validate accepts the answer.
+func Input(q, def string, validate func(string) error) (string, error) {
+	if !interactive() {
+		if validate != nil {
+			if err := validate(def); err != nil {
+				return def, err
+			}
+		}
+		return def, nil
+	}
+	hint := ""
+	if def != "" {
+		hint = "[" + def + "]"
+	}
+	for {
+		fmt.Fprint(Out, question(q, hint))
+		answer, err := readLine()
+		if err != nil {
+			return def, err
+		}
+		if answer = strings.TrimSpace(answer); answer == "" {
+			answer = def
+		}
+		if validate == nil {
+			return answer, nil
+		}
+		if err = validate(answer); err == nil {
+			return answer, nil
+		}
+		fmt.Fprintf(Out, "%s %s\n", term.CurrentTheme().Error.SprintFor(Out, "Invalid:"), err)
+	}
+}
+
+// Select asks for one of several options, returning the index of the chosen option or def if no choice is made
+//
+// On terminals which support it, options are chosen with the arrow keys. Otherwise, they are chosen by number.
+func Select(q string, options []string, def int) (int, error) {
+	if len(options) == 0 {
+		return def, errors.New("no options to select from")
+	}
+	if !interactive() {
+		return def, nil
+	}
+	if choice, err := selectRaw(q, options, def, false); err != errUnsupported {
+		if err != nil {
+			return def, err
+		}
+		return choice[0], nil
+	}
+	for i, option := range options {
+		fmt.Fprintf(Out, "  %d) %s\n", i+1, option)
+	}
+	for {
+		answer, err := Input(q, strconv.Itoa(def+1), nil)
+		if err != nil {
+			return def, err
+		}
+		if n, err := strconv.Atoi(answer); err == nil && n > 0 && n <= len(options) {
+			return n - 1, nil
+		}
+		fmt.Fprintf(Out, "Please choose a number between 1 and %d.\n", len(options))
+	}
+}
+
+// MultiSelect asks for any number of several options, returning the indices of the chosen options or defs if no
+// choice is made
+//
+// On terminals which support it, options are toggled with the space bar. Otherwise, they are chosen by a
+// comma-separated list of numbers.
+func MultiSelect(q string, options []string, defs []int) ([]int, error) {
+	if len(options) == 0 {
+		return defs, errors.New("no options to select from")
+	}
+	if !interactive() {
+		return defs, nil
+	}
+	if choices, err := selectRaw(q, options, 0, true, defs...); err != errUnsupported {
+		if err != nil {
+			return defs, err
+		}
+		return choices, nil
+	}
+	for i, option := range options {
+		fmt.Fprintf(Out, "  %d) %s\n", i+1, option)
+	}
+	var hint []string
+	for _, def := range defs {
+		hint = append(hint, strconv.Itoa(def+1))
+	}
+	for {
+		answer, err := Input(q, strings.Join(hint, ","), nil)
+		if err != nil {
+			return defs, err
+		}
+		if choices, ok := parseChoices(answer, len(options)); ok {
+			return choices, nil
+		}
+		fmt.Fprintf(Out, "Please choose numbers between 1 and %d, separated by commas.\n", len(options))
+	}
+}
+
+// parseChoices reads a comma-separated list of option numbers into indices
+func parseChoices(answer string, max int) (choices []int, ok bool) {
+	for _, field := range strings.Split(answer, ",") {
+		if field = strings.TrimSpace(field); field == "" {
+			continue
+		}
+		n, err := strconv.Atoi(field)
+		if err != nil || n < 1 || n > max {
+			return nil, false
+		}
+		choices = append(choices, n-1)
+	}
+	return choices, true
+}
+
+// Password asks for a secret, without echoing it to the terminal
+//
+// Echo is only disabled on Linux and the BSDs (including macOS). Elsewhere, Password fails with ErrNoEcho instead.
+func Password(q string) (string, error) {
+	if !interactive() {
+		return "", ErrNotInteractive
+	}
+	fmt.Fprint(Out, question(q, ""))
+	restore, err := disableEcho(In.Fd())
+	if err == errUnsupported {
+		return "", ErrNoEcho
+	}
+	if err != nil {
+		return "", err
+	}
+	// Ctrl-C still interrupts the read, so echo must be turned back on before exiting
+	interrupts := make(chan os.Signal, 1)
+	signal.Notify(interrupts, os.Interrupt)
+	done := make(chan struct{})
+	go func() {
+		select {
+		case <-interrupts:
+			restore()
+			fmt.Fprintln(Out)
+			os.Exit(130)
+		case <-done:
+		}
+	}()
+	answer, err := readLine()
+	close(done)
+	signal.Stop(interrupts)
+	restore()
+	fmt.Fprintln(Out)
+	return answer, err
+}
@@ -0,0 +1,139 @@
+//
+// Copyright 2017-2021 Bryan T. Meyers <root@datadrake.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package prompt
+
+import (
+	"errors"
+	"fmt"
+	"github.com/DataDrake/cli-ng/v2/term"
+	"strings"
+)
+
+// errUnsupported indicates that raw mode is not available for this terminal or platform
+var errUnsupported = errors.New("raw mode is not supported")
+
+// key is a single keypress, as read in raw mode
+type key int
+
+const (
+	keyOther key = iota
+	keyUp
+	keyDown
+	keySpace
+	keyEnter
+	keyInterrupt
+)
+
+// readKey waits for a single keypress
+func readKey() (key, error) {
+	buf := make([]byte, 8)
+	n, err := In.Read(buf)
+	if err != nil {
+		return keyOther, err
+	}
+	switch s := string(buf[:n]); s {
+	case "\033[A", "\033OA", "k":
+		return keyUp, nil
+	case "\033[B", "\033OB", "j":
+		return keyDown, nil
+	case " ":
+		return keySpace, nil
+	case "\r", "\n":
+		return keyEnter, nil
+	case "\003", "\004":
+		return keyInterrupt, nil
+	}
+	return keyOther, nil
+}
+
+// drawOptions prints the list of options, marking the cursor and any selected options
+func drawOptions(options []string, cursor int, selected []bool, multi bool) {
+	for i, option := range options {
+		marker := "  "
+		if i == cursor {
+			marker = "> "
+		}
+		box := ""
+		if multi {
+			box = "[ ] "
+			if selected[i] {
+				box = "[x] "
+			}
+		}
+		line := marker + box + option
+		if i == cursor {
+			line = term.Style{}.Bold().SprintFor(Out, line)
+		}
+		fmt.Fprintf(Out, "\r\033[K%s\r\n", line)
+	}
+}
+
+// selectRaw lets the user choose from options with the arrow keys, returning the chosen indices
+func selectRaw(q string, options []string, cursor int, multi bool, defs ...int) (chosen []int, err error) {
+	restore, err := makeRaw(In.Fd())
+	if err != nil {
+		return nil, errUnsupported
+	}
+	defer restore()
+	if cursor < 0 || cursor >= len(options) {
+		cursor = 0
+	}
+	selected := make([]bool, len(options))
+	for _, def := range defs {
+		if def >= 0 && def < len(options) {
+			selected[def] = true
+		}
+	}
+	hint := "(use arrow keys, enter to choose)"
+	if multi {
+		hint = "(use arrow keys, space to toggle, enter to choose)"
+	}
+	fmt.Fprint(Out, "\033[?25l"+question(q, hint)+"\r\n")
+	defer fmt.Fprint(Out, "\033[?25h")
+	drawOptions(options, cursor, selected, multi)
+	for {
+		k, err := readKey()
+		if err != nil {
+			return nil, err
+		}
+		switch k {
+		case keyUp:
+			cursor = (cursor - 1 + len(options)) % len(options)
+		case keyDown:
+			cursor = (cursor + 1) % len(options)
+		case keySpace:
+			if multi {
+				selected[cursor] = !selected[cursor]
+			}
+		case keyInterrupt:
+			fmt.Fprintf(Out, "\033[%dA\r\033[J", len(options))
+			return nil, ErrInterrupted
+		case keyEnter:
+			var names []string
+			for i, option := range options {
+				if (multi && selected[i]) || (!multi && i == cursor) {
+					chosen = append(chosen, i)
+					names = append(names, option)
+				}
+			}
+			fmt.Fprintf(Out, "\033[%dA\r\033[J  %s\r\n", len(options), strings.Join(names, ", "))
+			return chosen, nil
+		}
+		fmt.Fprintf(Out, "\033[%dA", len(options))
+		drawOptions(options, cursor, selected, multi)
+	}
+}
@@ -0,0 +1,39 @@
+//
+// Copyright 2017-2021 Bryan T. Meyers <root@datadrake.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+//go:build linux || darwin || dragonfly || freebsd || netbsd || openbsd
+// +build linux darwin dragonfly freebsd netbsd openbsd
+
+package prompt
+
+import (
+	"syscall"
+)
+
+// disableEcho stops the terminal at fd from echoing input, returning a function which restores it
+func disableEcho(fd uintptr) (restore func(), err error) {
+	old, err := getTermios(fd)
+	if err != nil {
+		return
+	}
+	quiet := old
+	quiet.Lflag &^= syscall.ECHO
+	if err = setTermios(fd, quiet); err != nil {
+		return
+	}
+	restore = func() { setTermios(fd, old) }
+	return
+}
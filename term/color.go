@@ -0,0 +1,114 @@
+//
+// Copyright 2017-2021 Bryan T. Meyers <root@datadrake.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package term
+
+import (
+	"io"
+	"os"
+	"regexp"
+)
+
+// colorEnabled controls whether or not formatting is emitted by this package
+var colorEnabled = DetectColor(os.Stdout)
+
+// stderrColor controls whether or not formatting is emitted for text written to Stderr
+var stderrColor = DetectColor(os.Stderr)
+
+// DetectColor checks if formatted output should be written to a file
+//
+// Formatting is disabled if NO_COLOR is set or TERM is "dumb", and forced if CLICOLOR_FORCE is set to a non-zero value.
+// Otherwise, formatting is only enabled for terminals.
+func DetectColor(f *os.File) bool {
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	if force := os.Getenv("CLICOLOR_FORCE"); force != "" && force != "0" {
+		return true
+	}
+	if os.Getenv("TERM") == "dumb" {
+		return false
+	}
+	return IsTerminal(f)
+}
+
+// SetColor enables or disables formatting for all of the helpers in this package, for both Stdout and Stderr
+func SetColor(enabled bool) {
+	colorEnabled = enabled
+	stderrColor = enabled
+}
+
+// ColorEnabled checks if formatting is currently enabled for Stdout
+func ColorEnabled() bool {
+	return colorEnabled
+}
+
+// ColorFor checks if formatting should be written to w
+//
+// Stdout and Stderr are detected separately, so that text written to a terminal on Stderr keeps its formatting when
+// Stdout is piped. A Progress uses the setting for Stderr, and other files are detected when formatting is enabled.
+func ColorFor(w io.Writer) bool {
+	switch v := w.(type) {
+	case *Progress:
+		return ColorFor(v.out)
+	case *progressWriter:
+		return ColorFor(v.out)
+	case *Writer:
+		return v.Color
+	case *os.File:
+		switch v {
+		case os.Stdout:
+			return colorEnabled
+		case os.Stderr:
+			return stderrColor
+		}
+		return colorEnabled && DetectColor(v)
+	}
+	return false
+}
+
+// escapes matches ANSI escape sequences
+var escapes = regexp.MustCompile("\033\\[[0-9;?]*[A-Za-z]")
+
+// Strip removes all ANSI escape sequences from a string
+func Strip(s string) string {
+	return escapes.ReplaceAllString(s, "")
+}
+
+// Writer removes formatting from everything written to it, unless Color is enabled
+type Writer struct {
+	W     io.Writer
+	Color bool
+}
+
+// NewWriter creates a Writer for a file, enabling Color if supported by the file
+func NewWriter(f *os.File) *Writer {
+	return &Writer{
+		W:     f,
+		Color: DetectColor(f),
+	}
+}
+
+// Write passes bytes through to the underlying Writer, stripping formatting if needed
+func (w *Writer) Write(p []byte) (n int, err error) {
+	if w.Color {
+		return w.W.Write(p)
+	}
+	if _, err = w.W.Write(escapes.ReplaceAll(p, nil)); err != nil {
+		return
+	}
+	return len(p), nil
+}
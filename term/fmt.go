@@ -18,15 +18,21 @@ package term
 
 // Reset clears all formatting
 func Reset(inner string) string {
+	if !colorEnabled {
+		return inner
+	}
 	return "\033[0m" + inner + "\033[0m"
 }
 
 // Resetln clears all formatting and adds a newline
 func Resetln(inner string) string {
-	return "\033[0m" + inner + "\033[0m\n"
+	return Reset(inner) + "\n"
 }
 
 // Bold formats text to be printed as bold
 func Bold(inner string) string {
+	if !colorEnabled {
+		return inner
+	}
 	return "\033[1m" + inner + "\033[0m"
 }
@@ -0,0 +1,408 @@
+//
+// Copyright 2017-2021 Bryan T. Meyers <root@datadrake.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package term
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// indicator is a single line of a Progress display
+type indicator interface {
+	// line renders the indicator for a terminal of the specified width
+	line(frame, width int) string
+	// status renders the indicator as a plain log line
+	status() string
+	// finished checks if the indicator has completed
+	finished() bool
+}
+
+// Progress renders any number of Bars and Spinners on Stderr
+//
+// Lines written through a Progress, or to its Stdout, are printed above the indicators, so that they are not clobbered.
+// When Stderr is not a terminal, the status of each indicator is printed periodically instead.
+type Progress struct {
+	// Interval is the minimum time between redraws on a terminal
+	Interval time.Duration
+	// LogInterval is the time between status lines when not on a terminal
+	LogInterval time.Duration
+
+	lock      sync.Mutex
+	out       io.Writer
+	tty       bool
+	stdoutTTY bool
+	items     []indicator
+	logged    map[indicator]bool
+	drawn     int
+	frame     int
+	dirty     bool
+	lastLog   time.Time
+	stop      chan struct{}
+	stopped   chan struct{}
+}
+
+// NewProgress creates a Progress which renders to Stderr
+func NewProgress() *Progress {
+	return &Progress{
+		Interval:    100 * time.Millisecond,
+		LogInterval: 5 * time.Second,
+		out:         os.Stderr,
+		tty:         IsTerminal(os.Stderr),
+		stdoutTTY:   IsTerminal(os.Stdout),
+		logged:      make(map[indicator]bool),
+	}
+}
+
+// add starts tracking a new indicator, starting the render loop if needed
+func (p *Progress) add(i indicator) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.items = append(p.items, i)
+	p.dirty = true
+	if p.stop == nil {
+		p.stop = make(chan struct{})
+		p.stopped = make(chan struct{})
+		p.lastLog = time.Now()
+		go p.loop(p.stop, p.stopped)
+	}
+}
+
+// loop redraws the indicators at a throttled rate until stopped
+func (p *Progress) loop(stop, stopped chan struct{}) {
+	ticker := time.NewTicker(p.Interval)
+	defer ticker.Stop()
+	defer close(stopped)
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			p.lock.Lock()
+			p.frame++
+			if p.tty && (p.dirty || p.animated()) {
+				p.redraw()
+			} else if !p.tty && time.Since(p.lastLog) >= p.LogInterval {
+				p.log()
+			}
+			p.lock.Unlock()
+		}
+	}
+}
+
+// animated checks if any indicators change on every frame, must be called with the lock held
+func (p *Progress) animated() bool {
+	for _, i := range p.items {
+		if _, ok := i.(*Spinner); ok && !i.finished() {
+			return true
+		}
+	}
+	return false
+}
+
+// update marks the indicators as changed
+func (p *Progress) update() {
+	p.lock.Lock()
+	p.dirty = true
+	if !p.tty {
+		p.logFinished()
+	}
+	p.lock.Unlock()
+}
+
+// clear erases the indicators from the terminal, must be called with the lock held
+func (p *Progress) clear() {
+	if p.drawn > 0 {
+		fmt.Fprintf(p.out, "\033[%dA\r\033[J", p.drawn)
+		p.drawn = 0
+	}
+}
+
+// redraw prints every indicator on its own line, must be called with the lock held
+func (p *Progress) redraw() {
+	width := 80
+	if _, cols, ok := getSize(os.Stderr.Fd()); ok && cols > 0 {
+		width = cols
+	}
+	var b strings.Builder
+	if p.drawn > 0 {
+		fmt.Fprintf(&b, "\033[%dA\r\033[J", p.drawn)
+	}
+	for _, i := range p.items {
+		b.WriteString(Truncate(i.line(p.frame, width), width-1) + "\n")
+	}
+	io.WriteString(p.out, b.String())
+	p.drawn = len(p.items)
+	p.dirty = false
+}
+
+// log prints the status of every unfinished indicator, must be called with the lock held
+func (p *Progress) log() {
+	for _, i := range p.items {
+		if !i.finished() {
+			fmt.Fprintln(p.out, i.status())
+		}
+	}
+	p.lastLog = time.Now()
+}
+
+// logFinished prints the final status of newly finished indicators, must be called with the lock held
+func (p *Progress) logFinished() {
+	for _, i := range p.items {
+		if i.finished() && !p.logged[i] {
+			fmt.Fprintln(p.out, i.status())
+			p.logged[i] = true
+		}
+	}
+}
+
+// Write prints output to Stderr above the indicators, without clobbering them
+//
+// This makes a Progress suitable as the output of a logger, while the indicators are shown.
+func (p *Progress) Write(b []byte) (n int, err error) {
+	return p.writeAbove(p.out, b)
+}
+
+// progressWriter writes to another file above the indicators of a Progress
+type progressWriter struct {
+	p   *Progress
+	out io.Writer
+}
+
+func (w *progressWriter) Write(b []byte) (n int, err error) {
+	return w.p.writeAbove(w.out, b)
+}
+
+// Stdout returns a Writer for Stdout, which clears the indicators from Stderr before each write and redraws them after
+//
+// Program output should be written here while the indicators are shown, since writing to Stdout directly clobbers them.
+func (p *Progress) Stdout() io.Writer {
+	return &progressWriter{p: p, out: os.Stdout}
+}
+
+// writeAbove clears the indicators, writes to out, and then redraws the indicators
+func (p *Progress) writeAbove(out io.Writer, b []byte) (n int, err error) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if p.tty {
+		p.clear()
+	}
+	n, err = out.Write(b)
+	if p.tty && len(p.items) > 0 {
+		// finish a partial line, unless it is going somewhere other than the terminal
+		if len(b) > 0 && b[len(b)-1] != '\n' && (out == p.out || p.stdoutTTY) {
+			io.WriteString(out, "\n")
+		}
+		p.redraw()
+	}
+	return
+}
+
+// Println prints a line of output to Stdout, above the indicators
+func (p *Progress) Println(a ...interface{}) {
+	fmt.Fprintln(p.Stdout(), a...)
+}
+
+// Printf prints formatted output to Stdout, above the indicators
+func (p *Progress) Printf(format string, a ...interface{}) {
+	fmt.Fprintf(p.Stdout(), format, a...)
+}
+
+// Stop ends rendering, leaving the final state of every indicator on screen
+func (p *Progress) Stop() {
+	p.lock.Lock()
+	stop, stopped := p.stop, p.stopped
+	p.stop = nil
+	p.lock.Unlock()
+	if stop == nil {
+		return
+	}
+	close(stop)
+	<-stopped
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if p.tty {
+		p.redraw()
+	} else {
+		p.logFinished()
+	}
+	p.items = nil
+	p.drawn = 0
+}
+
+// Bar is a progress bar for a task with a known amount of work
+type Bar struct {
+	p       *Progress
+	lock    sync.Mutex
+	name    string
+	current int64
+	total   int64
+	done    bool
+}
+
+// AddBar starts displaying a new Bar
+func (p *Progress) AddBar(name string, total int64) *Bar {
+	b := &Bar{
+		p:     p,
+		name:  name,
+		total: total,
+	}
+	p.add(b)
+	return b
+}
+
+// Add increases the amount of work completed
+func (b *Bar) Add(n int64) {
+	b.lock.Lock()
+	b.current += n
+	b.lock.Unlock()
+	b.p.update()
+}
+
+// Set replaces the amount of work completed
+func (b *Bar) Set(n int64) {
+	b.lock.Lock()
+	b.current = n
+	b.lock.Unlock()
+	b.p.update()
+}
+
+// Done marks the Bar as complete
+func (b *Bar) Done() {
+	b.lock.Lock()
+	b.current, b.done = b.total, true
+	b.lock.Unlock()
+	b.p.update()
+}
+
+func (b *Bar) ratio() float64 {
+	if b.total <= 0 {
+		return 0
+	}
+	if ratio := float64(b.current) / float64(b.total); ratio < 1 {
+		return ratio
+	}
+	return 1
+}
+
+func (b *Bar) line(frame, width int) string {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	count := fmt.Sprintf(" %3.0f%% %d/%d", b.ratio()*100, b.current, b.total)
+	size := width - StringWidth(b.name) - StringWidth(count) - 4
+	if size > 40 {
+		size = 40
+	}
+	if size < 10 {
+		size = 10
+	}
+	filled := int(b.ratio() * float64(size))
+	bar := strings.Repeat("=", filled)
+	if filled < size {
+		bar += ">" + strings.Repeat(" ", size-filled-1)
+	}
+	return b.name + " [" + bar + "]" + count
+}
+
+func (b *Bar) status() string {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	if b.done {
+		return fmt.Sprintf("%s: done", b.name)
+	}
+	return fmt.Sprintf("%s: %.0f%% (%d/%d)", b.name, b.ratio()*100, b.current, b.total)
+}
+
+func (b *Bar) finished() bool {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return b.done
+}
+
+// spinnerFrames are the animation of a Spinner
+var spinnerFrames = []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
+
+// Spinner is an indicator for a task with an unknown amount of work
+type Spinner struct {
+	p       *Progress
+	lock    sync.Mutex
+	name    string
+	message string
+	done    bool
+}
+
+// AddSpinner starts displaying a new Spinner
+func (p *Progress) AddSpinner(name string) *Spinner {
+	s := &Spinner{
+		p:    p,
+		name: name,
+	}
+	p.add(s)
+	return s
+}
+
+// SetMessage replaces the text displayed after the Spinner
+func (s *Spinner) SetMessage(message string) {
+	s.lock.Lock()
+	s.message = message
+	s.lock.Unlock()
+	s.p.update()
+}
+
+// Done marks the Spinner as complete
+func (s *Spinner) Done() {
+	s.lock.Lock()
+	s.done = true
+	s.lock.Unlock()
+	s.p.update()
+}
+
+func (s *Spinner) line(frame, width int) string {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	icon := spinnerFrames[frame%len(spinnerFrames)]
+	if s.done {
+		icon = "✓"
+	}
+	line := icon + " " + s.name
+	if s.message != "" {
+		line += ": " + s.message
+	}
+	return line
+}
+
+func (s *Spinner) status() string {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	switch {
+	case s.done:
+		return fmt.Sprintf("%s: done", s.name)
+	case s.message != "":
+		return fmt.Sprintf("%s: %s", s.name, s.message)
+	default:
+		return fmt.Sprintf("%s: working", s.name)
+	}
+}
+
+func (s *Spinner) finished() bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.done
+}
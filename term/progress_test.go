@@ -0,0 +1,43 @@
+//
+// Copyright 2017-2021 Bryan T. Meyers <root@datadrake.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package term
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestProgressIdleTerminalDoesNotLog(t *testing.T) {
+	var out bytes.Buffer
+	p := NewProgress()
+	p.out = &out
+	p.tty = true
+	p.Interval = time.Millisecond
+	p.LogInterval = time.Millisecond
+	bar := p.AddBar("dl", 10)
+	bar.Add(1)
+	time.Sleep(50 * time.Millisecond)
+	p.Stop()
+	if strings.Contains(out.String(), "dl: ") {
+		t.Errorf("status lines were logged to a terminal:\n%q", out.String())
+	}
+	if !strings.Contains(out.String(), "dl [") {
+		t.Errorf("bar was not drawn:\n%q", out.String())
+	}
+}
@@ -0,0 +1,49 @@
+//
+// Copyright 2017-2021 Bryan T. Meyers <root@datadrake.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package term
+
+import (
+	"os"
+	"strconv"
+)
+
+// Width returns the number of columns of the terminal attached to Stdout, or 0 if unknown
+func Width() int {
+	if _, cols, ok := getSize(os.Stdout.Fd()); ok && cols > 0 {
+		return cols
+	}
+	if cols, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && cols > 0 {
+		return cols
+	}
+	return 0
+}
+
+// Height returns the number of rows of the terminal attached to Stdout, or 0 if unknown
+func Height() int {
+	if rows, _, ok := getSize(os.Stdout.Fd()); ok && rows > 0 {
+		return rows
+	}
+	if rows, err := strconv.Atoi(os.Getenv("LINES")); err == nil && rows > 0 {
+		return rows
+	}
+	return 0
+}
+
+// IsTerminal checks if a file is attached to a terminal
+func IsTerminal(f *os.File) bool {
+	return isTerminal(f.Fd())
+}
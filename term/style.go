@@ -0,0 +1,373 @@
+//
+// Copyright 2017-2021 Bryan T. Meyers <root@datadrake.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package term
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Level is the number of colors supported by a terminal
+type Level int
+
+const (
+	// LevelNone disables colors
+	LevelNone Level = iota
+	// Level16 supports the 16 standard colors
+	Level16
+	// Level256 supports the 256 indexed colors
+	Level256
+	// LevelTrueColor supports 24-bit colors
+	LevelTrueColor
+)
+
+// colorLevel is the detected color support of Stdout, used whenever formatting is enabled
+var colorLevel = DetectLevel()
+
+// DetectLevel determines the color support of the terminal from COLORTERM and TERM
+func DetectLevel() Level {
+	switch os.Getenv("COLORTERM") {
+	case "truecolor", "24bit":
+		return LevelTrueColor
+	}
+	if strings.Contains(os.Getenv("TERM"), "256color") {
+		return Level256
+	}
+	return Level16
+}
+
+// SetLevel overrides the detected color support
+func SetLevel(level Level) {
+	colorLevel = level
+}
+
+// ColorLevel returns the color support currently in use, which is LevelNone if formatting is disabled
+func ColorLevel() Level {
+	if !colorEnabled {
+		return LevelNone
+	}
+	return colorLevel
+}
+
+// colorKind distinguishes the types of Color
+type colorKind uint8
+
+const (
+	kindNone colorKind = iota
+	kindBasic
+	kindIndexed
+	kindRGB
+)
+
+// Color is a foreground or background color, in one of the 16-color, 256-color, or 24-bit palettes
+type Color struct {
+	kind  colorKind
+	value uint32
+}
+
+// The 16 standard colors
+var (
+	Black         = Color{kindBasic, 0}
+	Red           = Color{kindBasic, 1}
+	Green         = Color{kindBasic, 2}
+	Yellow        = Color{kindBasic, 3}
+	Blue          = Color{kindBasic, 4}
+	Magenta       = Color{kindBasic, 5}
+	Cyan          = Color{kindBasic, 6}
+	White         = Color{kindBasic, 7}
+	BrightBlack   = Color{kindBasic, 8}
+	BrightRed     = Color{kindBasic, 9}
+	BrightGreen   = Color{kindBasic, 10}
+	BrightYellow  = Color{kindBasic, 11}
+	BrightBlue    = Color{kindBasic, 12}
+	BrightMagenta = Color{kindBasic, 13}
+	BrightCyan    = Color{kindBasic, 14}
+	BrightWhite   = Color{kindBasic, 15}
+)
+
+// Indexed creates a Color from the 256-color palette
+func Indexed(index uint8) Color {
+	return Color{kindIndexed, uint32(index)}
+}
+
+// RGB creates a 24-bit Color
+func RGB(r, g, b uint8) Color {
+	return Color{kindRGB, uint32(r)<<16 | uint32(g)<<8 | uint32(b)}
+}
+
+// rgb splits a 24-bit color into its components
+func (c Color) rgb() (r, g, b int) {
+	return int(c.value >> 16 & 0xFF), int(c.value >> 8 & 0xFF), int(c.value & 0xFF)
+}
+
+// basicRGB are the typical values of the 16 standard colors
+var basicRGB = [16][3]int{
+	{0, 0, 0}, {205, 0, 0}, {0, 205, 0}, {205, 205, 0},
+	{0, 0, 238}, {205, 0, 205}, {0, 205, 205}, {229, 229, 229},
+	{127, 127, 127}, {255, 0, 0}, {0, 255, 0}, {255, 255, 0},
+	{92, 92, 255}, {255, 0, 255}, {0, 255, 255}, {255, 255, 255},
+}
+
+// cubeLevels are the component values of the 6x6x6 color cube in the 256-color palette
+var cubeLevels = [6]int{0, 95, 135, 175, 215, 255}
+
+// toRGB converts any Color to a 24-bit Color
+func (c Color) toRGB() Color {
+	switch c.kind {
+	case kindBasic:
+		v := basicRGB[c.value]
+		return RGB(uint8(v[0]), uint8(v[1]), uint8(v[2]))
+	case kindIndexed:
+		switch i := int(c.value); {
+		case i < 16:
+			return Color{kindBasic, c.value}.toRGB()
+		case i < 232:
+			i -= 16
+			return RGB(uint8(cubeLevels[i/36]), uint8(cubeLevels[i/6%6]), uint8(cubeLevels[i%6]))
+		default:
+			gray := uint8(8 + (i-232)*10)
+			return RGB(gray, gray, gray)
+		}
+	}
+	return c
+}
+
+// downgrade converts a Color so that it is supported at the specified Level
+func (c Color) downgrade(level Level) Color {
+	switch {
+	case c.kind == kindRGB && level == Level256:
+		r, g, b := c.rgb()
+		if r == g && g == b {
+			switch {
+			case r < 8:
+				return Indexed(16)
+			case r > 238:
+				return Indexed(231)
+			default:
+				return Indexed(uint8(232 + (r-8)/10))
+			}
+		}
+		return Indexed(uint8(16 + 36*cubeIndex(r) + 6*cubeIndex(g) + cubeIndex(b)))
+	case c.kind == kindRGB && level == Level16, c.kind == kindIndexed && level == Level16:
+		if c.kind == kindIndexed && c.value < 16 {
+			return Color{kindBasic, c.value}
+		}
+		r, g, b := c.toRGB().rgb()
+		best, distance := 0, -1
+		for i, v := range basicRGB {
+			dr, dg, db := r-v[0], g-v[1], b-v[2]
+			if d := dr*dr + dg*dg + db*db; distance < 0 || d < distance {
+				best, distance = i, d
+			}
+		}
+		return Color{kindBasic, uint32(best)}
+	}
+	return c
+}
+
+// cubeIndex finds the closest level of the color cube for a single component
+func cubeIndex(v int) int {
+	if v < 48 {
+		return 0
+	}
+	if v < 115 {
+		return 1
+	}
+	return (v - 35) / 40
+}
+
+// codes returns the SGR parameters for this Color, as a foreground or background
+func (c Color) codes(level Level, background bool) []string {
+	c = c.downgrade(level)
+	offset := 30
+	if background {
+		offset = 40
+	}
+	switch c.kind {
+	case kindBasic:
+		if c.value < 8 {
+			return []string{strconv.Itoa(offset + int(c.value))}
+		}
+		return []string{strconv.Itoa(offset + 60 + int(c.value) - 8)}
+	case kindIndexed:
+		return []string{strconv.Itoa(offset + 8), "5", strconv.Itoa(int(c.value))}
+	case kindRGB:
+		r, g, b := c.rgb()
+		return []string{strconv.Itoa(offset + 8), "2", strconv.Itoa(r), strconv.Itoa(g), strconv.Itoa(b)}
+	}
+	return nil
+}
+
+// attribute is a text attribute other than color
+type attribute uint8
+
+const (
+	attrBold attribute = 1 << iota
+	attrDim
+	attrItalic
+	attrUnderline
+	attrStrikethrough
+)
+
+// attributeCodes are the SGR parameters for each attribute
+var attributeCodes = []struct {
+	attr attribute
+	code string
+}{
+	{attrBold, "1"},
+	{attrDim, "2"},
+	{attrItalic, "3"},
+	{attrUnderline, "4"},
+	{attrStrikethrough, "9"},
+}
+
+// Style is a composable set of colors and attributes (e.g. term.Style{}.Fg(term.Red).Bold())
+type Style struct {
+	fg    Color
+	bg    Color
+	attrs attribute
+}
+
+// Fg sets the foreground color
+func (s Style) Fg(c Color) Style {
+	s.fg = c
+	return s
+}
+
+// Bg sets the background color
+func (s Style) Bg(c Color) Style {
+	s.bg = c
+	return s
+}
+
+// Bold makes text bold
+func (s Style) Bold() Style {
+	s.attrs |= attrBold
+	return s
+}
+
+// Dim makes text dim
+func (s Style) Dim() Style {
+	s.attrs |= attrDim
+	return s
+}
+
+// Italic makes text italic
+func (s Style) Italic() Style {
+	s.attrs |= attrItalic
+	return s
+}
+
+// Underline makes text underlined
+func (s Style) Underline() Style {
+	s.attrs |= attrUnderline
+	return s
+}
+
+// Strikethrough makes text crossed out
+func (s Style) Strikethrough() Style {
+	s.attrs |= attrStrikethrough
+	return s
+}
+
+// Sequence returns the escape sequence that enables this Style, downgraded to the current Level
+func (s Style) Sequence() string {
+	return s.sequence(ColorLevel())
+}
+
+// sequence returns the escape sequence that enables this Style, downgraded to level
+func (s Style) sequence(level Level) string {
+	if level == LevelNone {
+		return ""
+	}
+	var codes []string
+	for _, attr := range attributeCodes {
+		if s.attrs&attr.attr != 0 {
+			codes = append(codes, attr.code)
+		}
+	}
+	codes = append(codes, s.fg.codes(level, false)...)
+	codes = append(codes, s.bg.codes(level, true)...)
+	if len(codes) == 0 {
+		return ""
+	}
+	return "\033[" + strings.Join(codes, ";") + "m"
+}
+
+// Sprint formats text with this Style
+func (s Style) Sprint(text string) string {
+	seq := s.Sequence()
+	if seq == "" {
+		return text
+	}
+	return seq + text + "\033[0m"
+}
+
+// SprintFor formats text with this Style, if formatting should be written to w (see ColorFor)
+func (s Style) SprintFor(w io.Writer, text string) string {
+	if !ColorFor(w) {
+		return text
+	}
+	seq := s.sequence(colorLevel)
+	if seq == "" {
+		return text
+	}
+	return seq + text + "\033[0m"
+}
+
+// Sprintf formats a string with this Style
+func (s Style) Sprintf(format string, a ...interface{}) string {
+	return s.Sprint(fmt.Sprintf(format, a...))
+}
+
+// Theme is a set of Styles for consistently formatting program output
+type Theme struct {
+	Heading     Style
+	TableHeader Style
+	Command     Style
+	Flag        Style
+	Error       Style
+	Warning     Style
+	Info        Style
+	Debug       Style
+}
+
+// DefaultTheme is the Theme used unless replaced by SetTheme
+var DefaultTheme = Theme{
+	Heading:     Style{}.Bold(),
+	TableHeader: Style{}.Bold(),
+	Error:       Style{}.Fg(Red).Bold(),
+	Warning:     Style{}.Fg(Yellow).Bold(),
+	Info:        Style{}.Fg(Cyan).Bold(),
+	Debug:       Style{}.Dim(),
+}
+
+// theme is the Theme currently in use
+var theme = DefaultTheme
+
+// SetTheme replaces the Theme currently in use
+func SetTheme(t Theme) {
+	theme = t
+}
+
+// CurrentTheme returns the Theme currently in use
+func CurrentTheme() Theme {
+	return theme
+}
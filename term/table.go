@@ -0,0 +1,301 @@
+//
+// Copyright 2017-2021 Bryan T. Meyers <root@datadrake.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package term
+
+import (
+	"io"
+	"strings"
+)
+
+// Align is the horizontal alignment of the cells in a Column
+type Align int
+
+const (
+	// AlignLeft pads cells on the right
+	AlignLeft Align = iota
+	// AlignRight pads cells on the left
+	AlignRight
+	// AlignCenter pads cells on both sides
+	AlignCenter
+)
+
+// Column describes a single column of a Table
+type Column struct {
+	Header   string
+	Align    Align
+	MaxWidth int
+	Wrap     bool
+}
+
+// Border is the set of characters used to draw the lines around and between the cells of a Table
+type Border struct {
+	Horizontal  string
+	Vertical    string
+	TopLeft     string
+	TopMid      string
+	TopRight    string
+	MidLeft     string
+	Mid         string
+	MidRight    string
+	BottomLeft  string
+	BottomMid   string
+	BottomRight string
+}
+
+var (
+	// BorderASCII draws a Table using only ASCII characters
+	BorderASCII = &Border{"-", "|", "+", "+", "+", "+", "+", "+", "+", "+", "+"}
+	// BorderLight draws a Table using light box-drawing characters
+	BorderLight = &Border{"─", "│", "┌", "┬", "┐", "├", "┼", "┤", "└", "┴", "┘"}
+	// BorderRounded draws a Table using light box-drawing characters with rounded corners
+	BorderRounded = &Border{"─", "│", "╭", "┬", "╮", "├", "┼", "┤", "╰", "┴", "╯"}
+)
+
+// minFitWidth is the narrowest that the last column will be made when fitting a Table to its Width
+const minFitWidth = 20
+
+// Table lays out cells in aligned columns, measuring them by their display width
+//
+// If Width is set, the last Column is shrunk to fit, wrapping or truncating its cells as needed. Without a Border,
+// columns are separated by Padding spaces and the whole Table is indented by Indent spaces.
+type Table struct {
+	Columns     []Column
+	Rows        [][]string
+	Border      *Border
+	Indent      int
+	Padding     int
+	Width       int
+	HideHeader  bool
+	HeaderStyle Style
+}
+
+// NewTable creates a left-aligned Table with the specified headers
+func NewTable(headers ...string) *Table {
+	t := &Table{
+		Padding: 2,
+	}
+	for _, header := range headers {
+		t.Columns = append(t.Columns, Column{Header: header})
+	}
+	return t
+}
+
+// AddRow appends a row of cells to the Table
+func (t *Table) AddRow(cells ...string) {
+	t.Rows = append(t.Rows, cells)
+}
+
+// Render writes out the Table
+func (t *Table) Render(w io.Writer) error {
+	_, err := io.WriteString(w, t.String())
+	return err
+}
+
+// String lays out the Table as text, with a newline after every line
+func (t *Table) String() string {
+	if len(t.Columns) == 0 {
+		return ""
+	}
+	widths := t.widths()
+	var b strings.Builder
+	t.writeRule(&b, widths, t.top())
+	if !t.HideHeader {
+		var headers []string
+		for _, c := range t.Columns {
+			headers = append(headers, c.Header)
+		}
+		t.writeRow(&b, widths, headers, true)
+		if len(t.Rows) > 0 {
+			t.writeRule(&b, widths, t.mid())
+		}
+	}
+	for _, row := range t.Rows {
+		t.writeRow(&b, widths, row, false)
+	}
+	t.writeRule(&b, widths, t.bottom())
+	return b.String()
+}
+
+// widths calculates the display width of each column, taking MaxWidth and Width into account
+func (t *Table) widths() []int {
+	widths := make([]int, len(t.Columns))
+	for i, c := range t.Columns {
+		if !t.HideHeader {
+			widths[i] = StringWidth(c.Header)
+		}
+	}
+	for _, row := range t.Rows {
+		for i := range t.Columns {
+			for _, line := range strings.Split(cell(row, i), "\n") {
+				if width := StringWidth(line); width > widths[i] {
+					widths[i] = width
+				}
+			}
+		}
+	}
+	for i, c := range t.Columns {
+		if c.MaxWidth > 0 && widths[i] > c.MaxWidth {
+			widths[i] = c.MaxWidth
+		}
+	}
+	if t.Width > 0 {
+		last := len(widths) - 1
+		total := t.Indent
+		for _, width := range widths {
+			total += width
+		}
+		if t.Border != nil {
+			total += (len(widths)+1)*StringWidth(t.Border.Vertical) + len(widths)*2
+		} else {
+			total += last * t.Padding
+		}
+		if over := total - t.Width; over > 0 {
+			fit := widths[last] - over
+			if fit < minFitWidth {
+				fit = minFitWidth
+			}
+			if fit < widths[last] {
+				widths[last] = fit
+			}
+		}
+	}
+	return widths
+}
+
+// cell gets a single cell from a row, which is empty if the row is too short
+func cell(row []string, i int) string {
+	if i < len(row) {
+		return row[i]
+	}
+	return ""
+}
+
+// lines breaks a cell into the lines which fit in its column
+func (t *Table) lines(text string, i, width int) (lines []string) {
+	for _, line := range strings.Split(text, "\n") {
+		switch {
+		case StringWidth(line) <= width:
+			lines = append(lines, line)
+		case t.Columns[i].Wrap:
+			for _, wrapped := range Wrap(line, width) {
+				lines = append(lines, Truncate(wrapped, width))
+			}
+		default:
+			lines = append(lines, Truncate(line, width))
+		}
+	}
+	return
+}
+
+// pad aligns text within width columns
+func pad(text string, width int, align Align) string {
+	space := width - StringWidth(text)
+	if space <= 0 {
+		return text
+	}
+	switch align {
+	case AlignRight:
+		return strings.Repeat(" ", space) + text
+	case AlignCenter:
+		return strings.Repeat(" ", space/2) + text + strings.Repeat(" ", space-space/2)
+	default:
+		return text + strings.Repeat(" ", space)
+	}
+}
+
+// writeRow writes out all of the lines of a single row
+func (t *Table) writeRow(b *strings.Builder, widths []int, row []string, header bool) {
+	cells := make([][]string, len(t.Columns))
+	height := 1
+	for i := range t.Columns {
+		cells[i] = t.lines(cell(row, i), i, widths[i])
+		if len(cells[i]) > height {
+			height = len(cells[i])
+		}
+	}
+	for l := 0; l < height; l++ {
+		var line strings.Builder
+		line.WriteString(strings.Repeat(" ", t.Indent))
+		if t.Border != nil {
+			line.WriteString(t.Border.Vertical)
+		}
+		for i, width := range widths {
+			var text string
+			if l < len(cells[i]) {
+				text = cells[i][l]
+			}
+			last := i == len(widths)-1
+			if !last || t.Border != nil || t.Columns[i].Align != AlignLeft {
+				text = pad(text, width, t.Columns[i].Align)
+			}
+			if header {
+				text = t.HeaderStyle.Sprint(text)
+			}
+			if t.Border != nil {
+				line.WriteString(" " + text + " " + t.Border.Vertical)
+				continue
+			}
+			line.WriteString(text)
+			if !last {
+				line.WriteString(strings.Repeat(" ", t.Padding))
+			}
+		}
+		b.WriteString(strings.TrimRight(line.String(), " ") + "\n")
+	}
+}
+
+// rule is the set of characters for a single horizontal line of a Border
+type rule struct {
+	left, mid, right string
+}
+
+func (t *Table) top() *rule {
+	if t.Border == nil {
+		return nil
+	}
+	return &rule{t.Border.TopLeft, t.Border.TopMid, t.Border.TopRight}
+}
+
+func (t *Table) mid() *rule {
+	if t.Border == nil {
+		return nil
+	}
+	return &rule{t.Border.MidLeft, t.Border.Mid, t.Border.MidRight}
+}
+
+func (t *Table) bottom() *rule {
+	if t.Border == nil {
+		return nil
+	}
+	return &rule{t.Border.BottomLeft, t.Border.BottomMid, t.Border.BottomRight}
+}
+
+// writeRule writes out a horizontal line of the Border, if any
+func (t *Table) writeRule(b *strings.Builder, widths []int, r *rule) {
+	if r == nil {
+		return
+	}
+	b.WriteString(strings.Repeat(" ", t.Indent))
+	b.WriteString(r.left)
+	for i, width := range widths {
+		if i > 0 {
+			b.WriteString(r.mid)
+		}
+		b.WriteString(strings.Repeat(t.Border.Horizontal, width+2))
+	}
+	b.WriteString(r.right + "\n")
+}
@@ -0,0 +1,118 @@
+//
+// Copyright 2017-2021 Bryan T. Meyers <root@datadrake.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package term
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// wide are the ranges of East Asian Wide and Fullwidth runes, including emoji, which take up two columns
+var wide = &unicode.RangeTable{
+	R16: []unicode.Range16{
+		{0x1100, 0x115F, 1}, {0x231A, 0x231B, 1}, {0x2329, 0x232A, 1},
+		{0x23E9, 0x23EC, 1}, {0x23F0, 0x23F3, 3}, {0x25FD, 0x25FE, 1},
+		{0x2614, 0x2615, 1}, {0x2648, 0x2653, 1}, {0x267F, 0x2693, 20},
+		{0x26A1, 0x26A1, 1}, {0x26AA, 0x26AB, 1}, {0x26BD, 0x26BE, 1},
+		{0x26C4, 0x26C5, 1}, {0x26CE, 0x26D4, 6}, {0x26EA, 0x26EA, 1},
+		{0x26F2, 0x26F3, 1}, {0x26F5, 0x26FA, 5}, {0x26FD, 0x26FD, 1},
+		{0x2705, 0x2705, 1}, {0x270A, 0x270B, 1}, {0x2728, 0x2728, 1},
+		{0x274C, 0x274E, 2}, {0x2753, 0x2755, 1}, {0x2757, 0x2757, 1},
+		{0x2795, 0x2797, 1}, {0x27B0, 0x27BF, 15}, {0x2B1B, 0x2B1C, 1},
+		{0x2B50, 0x2B55, 5}, {0x2E80, 0x303E, 1}, {0x3041, 0x33FF, 1},
+		{0x3400, 0x4DBF, 1}, {0x4E00, 0x9FFF, 1}, {0xA000, 0xA4CF, 1},
+		{0xA960, 0xA97F, 1}, {0xAC00, 0xD7A3, 1}, {0xF900, 0xFAFF, 1},
+		{0xFE10, 0xFE19, 1}, {0xFE30, 0xFE6F, 1}, {0xFF00, 0xFF60, 1},
+		{0xFFE0, 0xFFE6, 1},
+	},
+	R32: []unicode.Range32{
+		{0x16FE0, 0x16FE4, 1}, {0x17000, 0x18AFF, 1}, {0x1B000, 0x1B2FF, 1},
+		{0x1F004, 0x1F004, 1}, {0x1F0CF, 0x1F0CF, 1}, {0x1F18E, 0x1F18E, 1},
+		{0x1F191, 0x1F19A, 1}, {0x1F200, 0x1F251, 1}, {0x1F300, 0x1F64F, 1},
+		{0x1F680, 0x1F6FF, 1}, {0x1F900, 0x1F9FF, 1}, {0x1FA70, 0x1FAFF, 1},
+		{0x20000, 0x2FFFD, 1}, {0x30000, 0x3FFFD, 1},
+	},
+}
+
+// RuneWidth returns the number of columns needed to display a rune
+func RuneWidth(r rune) int {
+	switch {
+	case r == 0, unicode.IsControl(r), unicode.In(r, unicode.Mn, unicode.Me, unicode.Cf):
+		return 0
+	case unicode.Is(wide, r):
+		return 2
+	default:
+		return 1
+	}
+}
+
+// StringWidth returns the number of columns needed to display a string, ignoring any ANSI escape sequences
+func StringWidth(s string) (width int) {
+	for _, r := range Strip(s) {
+		width += RuneWidth(r)
+	}
+	return
+}
+
+// Ellipsis is appended to strings which have been truncated
+const Ellipsis = "…"
+
+// Truncate shortens a string to fit in width columns, ending it with an Ellipsis
+//
+// Escape sequences are preserved, and formatting is reset after the Ellipsis if any were present.
+func Truncate(s string, width int) string {
+	if StringWidth(s) <= width {
+		return s
+	}
+	if width <= 0 {
+		return ""
+	}
+	var b strings.Builder
+	used, formatted := 0, false
+	for len(s) > 0 {
+		if loc := escapeAt(s); loc > 0 {
+			b.WriteString(s[:loc])
+			s = s[loc:]
+			formatted = true
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s)
+		if used+RuneWidth(r) > width-1 {
+			break
+		}
+		b.WriteRune(r)
+		used += RuneWidth(r)
+		s = s[size:]
+	}
+	b.WriteString(Ellipsis)
+	if formatted {
+		b.WriteString("\033[0m")
+	}
+	return b.String()
+}
+
+// escapeAt returns the length of the escape sequence at the start of s, or 0 if there is none
+func escapeAt(s string) int {
+	if !strings.HasPrefix(s, "\033[") {
+		return 0
+	}
+	if loc := escapes.FindStringIndex(s); loc != nil && loc[0] == 0 {
+		return loc[1]
+	}
+	return 0
+}
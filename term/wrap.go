@@ -0,0 +1,58 @@
+//
+// Copyright 2017-2021 Bryan T. Meyers <root@datadrake.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package term
+
+import (
+	"strings"
+)
+
+// Wrap breaks text into lines of no more than width columns, splitting on whitespace
+//
+// Words longer than width are left on a line of their own. A width of zero or less disables wrapping.
+func Wrap(text string, width int) (lines []string) {
+	if width <= 0 {
+		return []string{text}
+	}
+	var line string
+	for _, word := range strings.Fields(text) {
+		switch {
+		case line == "":
+			line = word
+		case StringWidth(line)+1+StringWidth(word) > width:
+			lines = append(lines, line)
+			line = word
+		default:
+			line += " " + word
+		}
+	}
+	return append(lines, line)
+}
+
+// Indent wraps text to width and indents every line by indent columns
+//
+// The first line is indented by first columns instead, to allow for hanging indentation.
+func Indent(text string, width, first, indent int) string {
+	lines := Wrap(text, width-indent)
+	for i := range lines {
+		if i == 0 {
+			lines[i] = strings.Repeat(" ", first) + lines[i]
+		} else {
+			lines[i] = strings.Repeat(" ", indent) + lines[i]
+		}
+	}
+	return strings.Join(lines, "\n")
+}